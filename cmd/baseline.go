@@ -0,0 +1,104 @@
+// baseline.go - Implementation for the "baseline" command in SCANRUNNER-CLI
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/mtyiska/scanrunner/internal/baseline"
+	"github.com/mtyiska/scanrunner/internal/concurrency"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var baselinePath string
+
+// baselineCmd groups the subcommands that manage the baseline file
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Accept existing findings so only new ones are reported",
+	Long: `The baseline command records the current findings in a baseline file. validate and
+	report hide findings present in the baseline, so only newly introduced findings are reported.
+	Fingerprints do not depend on line numbers and survive unrelated edits.`,
+}
+
+// baselineCreateCmd writes every current finding to the baseline file
+var baselineCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Write every current finding to the baseline file",
+	Run: func(cmd *cobra.Command, args []string) {
+		results := currentResults(cmd)
+
+		b := baseline.New(results, config.ScanPath)
+		if err := b.Save(baselineFile()); err != nil {
+			fatal(exitInternal, "Error saving baseline: %v\n", err)
+		}
+		fmt.Printf("Baseline with %d findings written to %s\n", len(b.Findings), baselineFile())
+	},
+}
+
+// baselinePruneCmd drops baseline entries that no longer occur
+var baselinePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove baseline entries for findings that no longer occur",
+	Run: func(cmd *cobra.Command, args []string) {
+		b, err := baseline.Load(baselineFile())
+		if err != nil {
+			fatal(exitUsage, "Error loading baseline: %v\n", err)
+		}
+		if b == nil {
+			fatal(exitUsage, "No baseline found at %s\n", baselineFile())
+		}
+
+		removed := b.Prune(currentResults(cmd), config.ScanPath)
+		if err := b.Save(baselineFile()); err != nil {
+			fatal(exitInternal, "Error saving baseline: %v\n", err)
+		}
+		fmt.Printf("Removed %d stale entries; %d remain in %s\n", removed, len(b.Findings), baselineFile())
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{baselineCreateCmd, baselinePruneCmd} {
+		cmd.Flags().StringVarP(&rulesPath, "rules", "r", "", "Path to custom compliance rules file")
+		cmd.Flags().IntVarP(&jobs, "jobs", "j", concurrency.DefaultJobs(), "Number of files to validate in parallel")
+		cmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-validate every file instead of reusing cached results")
+		addBaselineFlag(cmd)
+		addHelmFlags(cmd)
+		baselineCmd.AddCommand(cmd)
+	}
+	rootCmd.AddCommand(baselineCmd)
+}
+
+// addBaselineFlag registers the --baseline flag on a command
+func addBaselineFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&baselinePath, "baseline", "", fmt.Sprintf("Path to the baseline file of accepted findings (default %s in the scan path)", baseline.DefaultFile))
+}
+
+// baselineFile returns the --baseline path, defaulting to the baseline file
+// in the scan path so it does not depend on the working directory
+func baselineFile() string {
+	if baselinePath != "" {
+		return baselinePath
+	}
+	return filepath.Join(config.ScanPath, baseline.DefaultFile)
+}
+
+// loadBaseline returns the findings accepted in the baseline file, or nil
+// when there is none
+func loadBaseline() *baseline.Baseline {
+	b, err := baseline.Load(baselineFile())
+	if err != nil {
+		fatal(exitUsage, "Error loading baseline: %v\n", err)
+	}
+	return b
+}
+
+// currentResults validates every file under the scan path with the configured
+// rules, without hiding baselined findings
+func currentResults(cmd *cobra.Command) []model.FileResult {
+	opts := scanOptions(cmd)
+	opts.Baseline = nil
+	return runScan(cmd, opts).Files
+}
@@ -0,0 +1,63 @@
+// cache.go - Implementation for the "cache" command in SCANRUNNER-CLI
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mtyiska/scanrunner/internal/cache"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var noCache bool
+
+// cacheCmd groups the subcommands that manage the validation result cache
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the validation result cache",
+	Long: `The cache command manages the on-disk cache of validation results. Results are
+	keyed by file content, rules and scanrunner version, so unchanged files are not re-validated.`,
+}
+
+// cacheCleanCmd removes every cached result
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove all cached validation results",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := cacheDir()
+		if err := cache.Clean(dir); err != nil {
+			return commandFailed(cmd, fmt.Errorf("failed to clean cache %s: %w", dir, err))
+		}
+		fmt.Printf("Cache cleaned: %s\n", dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheCleanCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// cacheDir returns the configured cache directory or the per-user default
+func cacheDir() string {
+	if config.CacheDir != "" {
+		return config.CacheDir
+	}
+	return cache.DefaultDir()
+}
+
+// openCache returns the result cache for the given rules, or nil when caching
+// is disabled with --no-cache or the cache directory cannot be used
+func openCache(rules model.Rules) *cache.Cache {
+	if noCache {
+		return nil
+	}
+	resultCache, err := cache.New(cacheDir(), version, rules)
+	if err != nil {
+		log.Printf("Result cache disabled: %v\n", err)
+		return nil
+	}
+	return resultCache
+}
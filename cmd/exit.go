@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/mtyiska/scanrunner/internal/engine"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by every command
+const (
+	exitClean    = 0 // Every file validated, none with findings at or above the fail threshold
+	exitFindings = 1 // Findings at or above the fail threshold
+	exitUsage    = 2 // Invalid flags, arguments, configuration, rules or input files, including files that cannot be parsed
+	exitInternal = 3 // Scanning, validating a file or writing output failed
+)
+
+var failOn string
+
+// internalError is an error returned from a command's RunE that is not caused
+// by its flags or arguments, such as failed I/O. Execute exits with
+// exitInternal for it and exitUsage for every other error.
+type internalError struct {
+	err error
+}
+
+func (e *internalError) Error() string { return e.err.Error() }
+func (e *internalError) Unwrap() error { return e.err }
+
+// commandFailed wraps an error a command hit while running so Execute exits
+// with exitInternal, and stops cobra from printing usage for it
+func commandFailed(cmd *cobra.Command, err error) error {
+	cmd.SilenceUsage = true
+	return &internalError{err: err}
+}
+
+// exitCode returns the exit code for an error returned by a command
+func exitCode(err error) int {
+	var internal *internalError
+	if errors.As(err, &internal) {
+		return exitInternal
+	}
+	return exitUsage
+}
+
+// resultExitCode returns the exit code for a completed scan. Files that could
+// not be validated take precedence over findings: they exit with exitUsage
+// when every one of them has content that cannot be parsed, else with
+// exitInternal.
+func resultExitCode(result *engine.Result) int {
+	switch {
+	case result.Errored():
+		for _, file := range result.Files {
+			if file.Errored() && !file.InvalidInput {
+				return exitInternal
+			}
+		}
+		return exitUsage
+	case result.Failed():
+		return exitFindings
+	}
+	return exitClean
+}
+
+// fatal logs the message and exits with code
+func fatal(code int, format string, args ...interface{}) {
+	log.Printf(format, args...)
+	os.Exit(code)
+}
+
+// scanExitCode classifies a scanDirectory error: a missing scan path is a
+// configuration error, anything else an internal one
+func scanExitCode(err error) int {
+	if errors.Is(err, fs.ErrNotExist) {
+		return exitUsage
+	}
+	return exitInternal
+}
+
+// failThreshold returns the severity from --fail-on, or from the configuration
+// when the flag is not set
+func failThreshold() string {
+	threshold := failOn
+	if threshold == "" {
+		threshold = config.FailOn
+	}
+	if threshold == "" {
+		threshold = model.DefaultThreshold
+	}
+	if !model.ValidSeverity(threshold) {
+		fatal(exitUsage, "Invalid fail threshold %q (want one of %v)\n", threshold, model.Severities)
+	}
+	return threshold
+}
+
+// addFailOnFlag registers the --fail-on flag on a command
+func addFailOnFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&failOn, "fail-on", "", fmt.Sprintf("Lowest severity that fails a file: one of %v (default from fail_on in the config, else %s)", model.Severities, model.DefaultThreshold))
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/mtyiska/scanrunner/internal/ignore"
+)
+
+var ignores *ignore.List
+
+// loadIgnores returns the ignore section of the configuration merged with the
+// ignore file in the scan path, loading them on first use
+func loadIgnores() *ignore.List {
+	if ignores != nil {
+		return ignores
+	}
+
+	fileList, err := ignore.Load(filepath.Join(config.ScanPath, ignore.FileName))
+	if err != nil {
+		fatal(exitUsage, "Error loading ignore file: %v\n", err)
+	}
+	// Merge into an empty list so the configuration's slices are never appended to
+	var list ignore.List
+	list.Merge(&config.Ignore)
+	list.Merge(fileList)
+	if err := list.Compile(); err != nil {
+		fatal(exitUsage, "Invalid ignore configuration: %v\n", err)
+	}
+	ignores = &list
+	return ignores
+}
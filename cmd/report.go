@@ -1,14 +1,16 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
-	"github.com/mtyiska/scanrunner/internal/compliance"
-	"github.com/mtyiska/scanrunner/pkg"
+	"github.com/mtyiska/scanrunner/internal/concurrency"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/report"
 	"github.com/spf13/cobra"
 )
 
@@ -16,100 +18,258 @@ var reportCmd = &cobra.Command{
 	Use:   "report",
 	Short: "Generate a summary report from validation results",
 	Long: `The report command aggregates and formats validation results from the
-	validate command into a readable report. Reports can be output as JSON, Markdown,
-	or other formats, based on user preference.`,
+	validate command into a readable report. Reports can be output as versioned JSON
+	(see "scanrunner report schema"), Markdown,
+	SARIF 2.1.0 for code scanning upload, JUnit XML for CI test dashboards, or a
+	self-contained HTML page, based on user preference. Repeat --format FORMAT=PATH
+	to write several formats from one scan; a PATH of "-" writes to stdout. Like validate,
+	it exits with status 1 after writing the reports when any file fails at the --fail-on
+	threshold, and with status 2 or 3 when files could not be validated.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		log.Printf("Generating report in format: %s\n", config.OutputFormat)
-		log.Printf("Saving report to: %s\n", config.ReportOutput)
-
-		// Scan the directory for YAML files to validate
-		files, err := scanDirectory(config.ScanPath)
+		targets, err := reportTargets()
 		if err != nil {
-			log.Fatalf("Error scanning directory: %v\n", err)
+			fatal(exitUsage, "Invalid report output: %v\n", err)
 		}
-		if len(files) == 0 {
+
+		// Validate every file, hiding baselined findings
+		opts := scanOptions(cmd)
+		result := runScan(cmd, opts)
+		if len(result.Files) == 0 {
 			log.Println("No files found for validation. Report generation skipped.")
 			return
 		}
-
-		// Load validation rules
-		rules, err := pkg.LoadRules(config.RulesPath)
-		if err != nil {
-			log.Fatalf("Failed to load validation rules: %v\n", err)
+		meta := report.Metadata{
+			Version:    version,
+			ConfigHash: report.Hash(config),
+			RulesHash:  report.Hash(opts.Rules),
+			StartedAt:  result.StartedAt,
+			FinishedAt: result.FinishedAt,
 		}
 
-		// Validate each file and store results
-		var results []map[string]interface{}
-		for _, file := range files {
-			// log.Printf("Validating file: %s\n", file)
-			err := compliance.ValidateFile(file, rules)
+		// Format and save each report
+		for _, target := range targets {
+			content, err := formatReport(result.Files, target.Format, meta, opts.Threshold)
 			if err != nil {
-				// log.Printf("Validation failed for file %s: %v\n", file, err)
-				results = append(results, map[string]interface{}{
-					"file":   filepath.Base(file),
-					"status": "FAIL",
-					"error":  err.Error(),
-				})
-				continue
+				fatal(exitInternal, "Error formatting %s report: %v\n", target.Format, err)
 			}
+			if err := saveReport(content, target.Path); err != nil {
+				fatal(exitInternal, "Error saving report: %v\n", err)
+			}
+			if target.Path != "-" {
+				log.Printf("%s report written to %s\n", target.Format, target.Path)
+			}
+		}
 
-			results = append(results, map[string]interface{}{
-				"file":   filepath.Base(file),
-				"status": "PASS",
-			})
+		// Fail like validate does, once every report is written
+		switch code := resultExitCode(result); code {
+		case exitFindings:
+			fatal(code, "Files failed at the %s threshold; they are listed in the report.\n", opts.Threshold)
+		case exitUsage, exitInternal:
+			fatal(code, "Some files could not be validated; they are listed in the report.\n")
 		}
+	},
+}
+
+// reportSchemaCmd prints the JSON Schema of the JSON report
+var reportSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the JSON report format",
+	Run: func(cmd *cobra.Command, args []string) {
+		schema, err := report.Schema()
+		if err != nil {
+			fatal(exitInternal, "Error reading report schema: %v\n", err)
+		}
+		fmt.Print(string(schema))
+	},
+}
+
+var reportFormats []string
+var reportOutput string
+var diffFormat string
 
-		// Format and save the report
-		reportContent := formatReport(results, config.OutputFormat)
-		err = saveReport(reportContent, config.ReportOutput)
+// reportDiffCmd compares two JSON reports
+var reportDiffCmd = &cobra.Command{
+	Use:   "diff OLD.json NEW.json",
+	Short: "Show findings introduced, fixed and unchanged between two JSON reports",
+	Long: `The diff command compares two JSON reports, for example from the main branch and a
+	pull request, matching findings by their stable fingerprints. It exits with status 1
+	when findings of the --fail-on severity or above were introduced, unless --fail-on
+	is "none".`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		previous, err := loadReport(args[0])
 		if err != nil {
-			log.Fatalf("Error saving report: %v\n", err)
+			fatal(exitUsage, "Error loading report: %v\n", err)
+		}
+		current, err := loadReport(args[1])
+		if err != nil {
+			fatal(exitUsage, "Error loading report: %v\n", err)
+		}
+
+		diff := report.Compare(previous, current)
+		switch diffFormat {
+		case "json":
+			content, err := diff.JSON()
+			if err != nil {
+				fatal(exitInternal, "Error formatting report diff: %v\n", err)
+			}
+			fmt.Println(string(content))
+		case "markdown":
+			fmt.Print(diff.Markdown())
+		default:
+			fatal(exitUsage, "Unknown diff format: %s (want markdown or json)\n", diffFormat)
 		}
 
-		fmt.Println("Report successfully generated.")
+		if failOn != "none" && diff.IntroducedAtLeast(failThreshold()) {
+			os.Exit(exitFindings)
+		}
 	},
 }
 
 func init() {
+	reportDiffCmd.Flags().StringVar(&diffFormat, "format", "markdown", "Output format: markdown or json")
+	addFailOnFlag(reportDiffCmd)
+	reportCmd.AddCommand(reportDiffCmd)
+	reportCmd.AddCommand(reportSchemaCmd)
+	reportCmd.Flags().BoolVar(&strictMode, "strict", false, "Exit with status 1 when any file fails")
+	reportCmd.Flags().MarkDeprecated("strict", "report always exits with status 1 when a file fails at the --fail-on threshold")
+	reportCmd.Flags().StringVarP(&rulesPath, "rules", "r", "", "Path to custom compliance rules file")
+	reportCmd.Flags().IntVarP(&jobs, "jobs", "j", concurrency.DefaultJobs(), "Number of files to validate in parallel")
+	reportCmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-validate every file instead of reusing cached results")
+	addHelmFlags(reportCmd)
+	addChangeFlags(reportCmd)
+	addBaselineFlag(reportCmd)
+	addFailOnFlag(reportCmd)
+	reportCmd.Flags().StringArrayVar(&reportFormats, "format", nil, "Report format, optionally with its own path as FORMAT=PATH: json, sarif, junit, html or markdown (can be repeated; default output_format from the config)")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", `Path for a --format given without one, or "-" for stdout (default report_output from the config)`)
 	rootCmd.AddCommand(reportCmd)
 }
 
-// formatReport formats the validation results based on the desired output format
-func formatReport(results []map[string]interface{}, format string) string {
+// reportTarget is one report to write: its format and path, "-" for stdout
+type reportTarget struct {
+	Format string
+	Path   string
+}
+
+// reportExtensions lists the file extensions accepted for each report format
+var reportExtensions = map[string][]string{
+	"json":     {".json"},
+	"sarif":    {".sarif", ".json"},
+	"junit":    {".xml"},
+	"html":     {".html", ".htm"},
+	"markdown": {".md", ".markdown"},
+}
+
+// reportTargets resolves the --format and --output flags, falling back to the
+// configured format and path. Unknown formats, extensions that do not match
+// the format and paths used twice are rejected.
+func reportTargets() ([]reportTarget, error) {
+	output := reportOutput
+	if output == "" {
+		output = config.ReportOutput
+	}
+	formats := reportFormats
+	if len(formats) == 0 {
+		formats = []string{config.OutputFormat}
+	}
+
+	var targets []reportTarget
+	seen := make(map[string]bool)
+	for _, format := range formats {
+		target := reportTarget{Format: format, Path: output}
+		if name, path, ok := strings.Cut(format, "="); ok {
+			target = reportTarget{Format: name, Path: path}
+		}
+
+		extensions, ok := reportExtensions[target.Format]
+		if !ok {
+			return nil, fmt.Errorf("unknown format %q (want json, sarif, junit, html or markdown)", target.Format)
+		}
+		if target.Path == "" {
+			return nil, fmt.Errorf("no path given for the %s report", target.Format)
+		}
+		if ext := strings.ToLower(filepath.Ext(target.Path)); target.Path != "-" && ext != "" && !slices.Contains(extensions, ext) {
+			return nil, fmt.Errorf("%s: extension %s does not match the %s format (want %s)", target.Path, ext, target.Format, strings.Join(extensions, " or "))
+		}
+		if seen[target.Path] {
+			return nil, fmt.Errorf("%s is the output of more than one format; use --format FORMAT=PATH", target.Path)
+		}
+		seen[target.Path] = true
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// formatReport formats the validation results in the given format; threshold
+// is the lowest severity reported as a failure
+func formatReport(results []model.FileResult, format string, meta report.Metadata, threshold string) ([]byte, error) {
 	switch format {
+	case "sarif":
+		return report.SARIF(results, config.ScanPath, version)
+	case "junit":
+		return report.JUnit(results, config.ScanPath, threshold)
+	case "html":
+		return report.HTML(results, config.ScanPath, version, meta.FinishedAt)
 	case "json":
-		jsonContent, err := json.MarshalIndent(results, "", "  ")
-		if err != nil {
-			log.Printf("Error formatting JSON report: %v\n", err)
-			return ""
-		}
-		return string(jsonContent)
+		return report.New(results, config.ScanPath, meta).JSON()
 	case "markdown":
 		report := "# Validation Report\n\n"
-		for _, result := range results {
-			file := result["file"].(string)
-			status := result["status"].(string)
-			report += fmt.Sprintf("- **%s**: %s\n", file, status)
-			if status == "FAIL" {
-				errorMsg := result["error"].(string)
-				report += fmt.Sprintf("  - %s\n", errorMsg)
+		for _, result := range relativePaths(results, config.ScanPath) {
+			report += fmt.Sprintf("- **%s**: %s\n", result.File, result.Status)
+			if result.Error != "" {
+				report += fmt.Sprintf("  - %s\n", result.Error)
+			}
+			for _, finding := range result.Findings {
+				report += fmt.Sprintf("  - %s\n", formatFinding(result.File, finding))
 			}
 		}
-		return report
+		return []byte(report), nil
 	default:
-		log.Printf("Unknown format: %s. Defaulting to markdown.\n", format)
-		return formatReport(results, "markdown")
+		return nil, fmt.Errorf("unknown format %q", format)
 	}
 }
 
-// saveReport writes the report content to a specified file
-func saveReport(content, path string) error {
+// relativePaths returns a copy of results with the paths of files and their
+// findings made relative to root, so headings and finding lines agree
+func relativePaths(results []model.FileResult, root string) []model.FileResult {
+	relative := make([]model.FileResult, len(results))
+	for i, result := range results {
+		relative[i] = result
+		relative[i].File = model.RelativePath(result.File, root)
+		relative[i].Findings = make([]model.Finding, len(result.Findings))
+		for j, finding := range result.Findings {
+			if finding.File != "" {
+				finding.File = model.RelativePath(finding.File, root)
+			}
+			relative[i].Findings[j] = finding
+		}
+	}
+	return relative
+}
+
+// loadReport reads a JSON report written by the report command
+func loadReport(path string) (*report.Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return report.Load(data)
+}
+
+// saveReport writes the report content to a specified file, or to stdout
+// when path is "-"
+func saveReport(content []byte, path string) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+
 	file, err := os.Create(path)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	_, err = file.WriteString(content)
+	_, err = file.Write(content)
 	return err
 }
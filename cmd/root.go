@@ -3,10 +3,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/mtyiska/scanrunner/internal/model"
 	"github.com/mtyiska/scanrunner/pkg"
 	"github.com/spf13/cobra"
 )
@@ -14,7 +16,6 @@ import (
 var (
 	configFile string     // Variable to hold the path to the config file
 	config     pkg.Config // Variable to store the loaded configuration
-	rules      model.Rules  // Variable to store the loaded rules
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -22,7 +23,13 @@ var rootCmd = &cobra.Command{
 	Use:   "scanrunner",
 	Short: "ScanRunner-CLI: A modular tool for file validation and AI-powered insights",
 	Long: `ScanRunner-CLI is a flexible command-line tool for scanning, validating,
-and reporting on YAML/JSON files while leveraging AI for actionable suggestions.`,
+and reporting on YAML/JSON files while leveraging AI for actionable suggestions.
+
+Exit codes:
+  0  no findings at or above the fail threshold (--fail-on)
+  1  findings at or above the fail threshold
+  2  invalid flags, arguments, configuration, rules or input files
+  3  scanning, validation, writing output or other file operations failed`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Load the configuration file
 		var err error
@@ -31,28 +38,28 @@ and reporting on YAML/JSON files while leveraging AI for actionable suggestions.
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
 
-		// Load the rules file using the path specified in the config
-		rules, err = pkg.LoadRules(config.RulesPath)
-		if err != nil {
-			return fmt.Errorf("failed to load rules: %w", err)
-		}
-
 		// log.Printf("Configuration loaded: %+v\n", config)
-		// log.Printf("Rules loaded: %+v\n", rules)
 		return nil
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is the entry point for the CLI.
+// Ctrl-C or SIGTERM cancels the command context so in-flight validation stops cleanly.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
+	// Execute prints errors itself, once
+	rootCmd.SilenceErrors = true
+
 	// Add a persistent flag for specifying the configuration file
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "./config/default-config.yaml",
 		"Path to the configuration file (default is ./config/default-config.yaml)")
@@ -1,12 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
-	"strings"
 
+	"github.com/mtyiska/scanrunner/internal/engine"
+	"github.com/mtyiska/scanrunner/internal/vcs"
 	"github.com/spf13/cobra"
 )
 
@@ -19,14 +19,21 @@ var scanCmd = &cobra.Command{
 		log.Printf("Scanning directory: %s\n", config.ScanPath)
 
 		// Scan the directory specified in config.ScanPath
-		files, err := scanDirectory(config.ScanPath)
+		files, err := engine.Discover(config.ScanPath, loadIgnores(), config.DockerfilePatterns)
 		if err != nil {
-			log.Fatalf("Error scanning directory: %v\n", err)
+			fatal(scanExitCode(err), "Error scanning directory: %v\n", err)
 		}
 
+		// Restrict the listing to changed files when requested
+		changes, err := loadChanges(cmd.Context())
+		if err != nil {
+			fatal(exitUsage, "Error reading Git changes: %v\n", err)
+		}
+		files = changes.Filter(files)
+
 		// Output the discovered files
 		if len(files) == 0 {
-			fmt.Println("No YAML, JSON files, or Dockerfiles found.")
+			fmt.Println("No YAML, JSON, Terraform files, or Dockerfiles found.")
 		} else {
 			fmt.Println("Discovered files:")
 			for _, file := range files {
@@ -36,33 +43,30 @@ var scanCmd = &cobra.Command{
 	},
 }
 
+var changedSince string
+var stagedOnly bool
+
 func init() {
+	addChangeFlags(scanCmd)
 	rootCmd.AddCommand(scanCmd)
 }
 
-// scanDirectory scans the directory for YAML, JSON files, and Dockerfiles
-func scanDirectory(path string) ([]string, error) {
-	var files []string
-
-	err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip directories
-		if info.IsDir() {
-			return nil
-		}
-
-		// Check for YAML, JSON, or files with "Docker" in their name
-		ext := strings.ToLower(filepath.Ext(filePath))
-		baseName := strings.ToLower(filepath.Base(filePath))
-		if ext == ".yaml" || ext == ".yml" || ext == ".json" || strings.Contains(baseName, "docker") {
-			files = append(files, filePath)
-		}
-
-		return nil
-	})
+// addChangeFlags registers the flags that restrict a scan to Git changes
+func addChangeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&changedSince, "changed-since", "", "Only report on files changed relative to this Git ref (e.g. origin/main)")
+	cmd.Flags().BoolVar(&stagedOnly, "staged", false, "Only report on files staged in the Git index")
+}
 
-	return files, err
+// loadChanges returns the change set selected by --changed-since or --staged,
+// or nil when neither is set so that every file is reported
+func loadChanges(ctx context.Context) (*vcs.ChangeSet, error) {
+	switch {
+	case changedSince != "" && stagedOnly:
+		return nil, fmt.Errorf("--changed-since and --staged cannot be used together")
+	case changedSince != "":
+		return vcs.ChangedSince(ctx, config.ScanPath, changedSince)
+	case stagedOnly:
+		return vcs.Staged(ctx, config.ScanPath)
+	}
+	return nil, nil
 }
@@ -2,10 +2,18 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
-	"github.com/mtyiska/scanrunner/internal/compliance"
+	"github.com/mtyiska/scanrunner/internal/concurrency"
+	"github.com/mtyiska/scanrunner/internal/engine"
+	"github.com/mtyiska/scanrunner/internal/helm"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/report"
 	"github.com/mtyiska/scanrunner/pkg"
 
 	"github.com/spf13/cobra"
@@ -13,6 +21,11 @@ import (
 
 var strictMode bool
 var rulesPath string
+var helmValues helm.ValuesOptions
+var jobs int
+var changedLinesOnly bool
+var outputStyle string
+var quiet bool
 
 // validateCmd represents the "validate" subcommand
 var validateCmd = &cobra.Command{
@@ -21,57 +34,172 @@ var validateCmd = &cobra.Command{
 	Long: `The validate command checks YAML, JSON files, and Dockerfiles for structural correctness
 	and compliance with predefined rules (e.g., field presence, best practices, etc.).`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// log.Printf("Validating files in directory: %s\n", config.ScanPath)
-
-		// Load compliance rules
-		if rulesPath == "" {
-			rulesPath = config.RulesPath
-		}
-		rules, err := pkg.LoadRules(rulesPath)
-		if err != nil {
-			log.Fatalf("Failed to load rules: %v\n", err)
+		if outputStyle != "table" && outputStyle != "plain" {
+			fatal(exitUsage, "Unknown output style: %s (want table or plain)\n", outputStyle)
 		}
-		// log.Printf("Loaded rules: %+v\n", rules)
 
-		// Scan the directory for YAML files
-		files, err := scanDirectory(config.ScanPath)
-		if err != nil {
-			log.Fatalf("Error scanning directory: %v\n", err)
+		// With --changed-since or --staged, charts and overlays still load every
+		// file for context, but only changed files are reported
+		opts := scanOptions(cmd)
+		if changedLinesOnly && opts.Changes == nil {
+			fatal(exitUsage, "--changed-lines-only requires --changed-since or --staged\n")
 		}
-		if len(files) == 0 {
+		opts.ChangedLinesOnly = changedLinesOnly
+
+		result := runScan(cmd, opts)
+		if len(result.Files) == 0 {
 			log.Println("No YAML, JSON files, or Dockerfiles found for validation.")
 			return
 		}
 
-		// Validate each file against the rules
-		var validationErrors []string
-		for _, file := range files {
-			// log.Printf("Validating file: %s\n", file)
-			err := compliance.ValidateFile(file, rules)
-			if err != nil {
-				// log.Printf("Validation failed for %s: %v\n", file, err)
-				validationErrors = append(validationErrors, fmt.Sprintf("%s: FAIL (%v)", file, err))
-				if strictMode {
-					log.Fatal("Strict mode enabled. Stopping on first error.")
+		if strictMode || config.StrictMode {
+			for _, file := range result.Files {
+				if file.Status == model.StatusFail {
+					printResults([]model.FileResult{file}, opts.Threshold)
+					fatal(resultExitCode(&engine.Result{Files: []model.FileResult{file}}), "Strict mode enabled. Stopping on first failure.\n")
 				}
-			} else {
-				// log.Printf("Validation passed for %s\n", file)
-				validationErrors = append(validationErrors, fmt.Sprintf("%s: PASS", file))
 			}
 		}
 
 		// Print final validation results
-		fmt.Println("\nValidation Results:")
-		for _, result := range validationErrors {
-			fmt.Println(result)
+		printResults(result.Files, opts.Threshold)
+		if code := resultExitCode(result); code != exitClean {
+			os.Exit(code)
 		}
 	},
 }
 
+// scanOptions returns the engine options set by the configuration and the
+// flags shared by every command that validates files
+func scanOptions(cmd *cobra.Command) engine.Options {
+	path := config.RulesPath
+	if rulesPath != "" {
+		path = rulesPath
+	}
+	rules, err := pkg.LoadRules(path)
+	if err != nil {
+		fatal(exitUsage, "Failed to load rules: %v\n", err)
+	}
+	changes, err := loadChanges(cmd.Context())
+	if err != nil {
+		fatal(exitUsage, "Error reading Git changes: %v\n", err)
+	}
+
+	return engine.Options{
+		Root:               config.ScanPath,
+		Rules:              rules,
+		DockerfilePatterns: config.DockerfilePatterns,
+		Helm:               helmValues,
+		Jobs:               jobs,
+		Cache:              openCache(rules),
+		Changes:            changes,
+		Ignore:             loadIgnores(),
+		Baseline:           loadBaseline(),
+		Threshold:          failThreshold(),
+	}
+}
+
+// runScan runs the engine, reporting expired ignore entries and findings
+// hidden by the baseline
+func runScan(cmd *cobra.Command, opts engine.Options) *engine.Result {
+	result, err := engine.Run(cmd.Context(), opts)
+	switch {
+	case errors.Is(err, context.Canceled):
+		fatal(exitInternal, "Validation interrupted: %v\n", err)
+	case err != nil:
+		fatal(scanExitCode(err), "Error scanning directory: %v\n", err)
+	}
+
+	for _, entry := range result.Expired {
+		log.Printf("Warning: ignore entry owned by %s expired on %s and no longer applies (rules %v, paths %v, resources %v)\n",
+			entry.Owner, entry.Expires, entry.Rules, entry.Paths, entry.Resources)
+	}
+	if result.Baselined > 0 {
+		log.Printf("%d baselined findings hidden (%s)\n", result.Baselined, baselineFile())
+	}
+	return result
+}
+
+// addHelmFlags registers the values overrides used when rendering Helm charts
+func addHelmFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&helmValues.ValueFiles, "values", nil, "Helm values file applied when rendering charts (can be repeated)")
+	cmd.Flags().StringArrayVar(&helmValues.Values, "set", nil, "Helm value override in key=value form (can be repeated)")
+}
+
+// printResults prints results in the style chosen with --output-style
+func printResults(results []model.FileResult, threshold string) {
+	if outputStyle == "table" {
+		fmt.Print(report.Table(results, config.ScanPath, report.TableOptions{
+			Color:     colorEnabled(),
+			Quiet:     quiet,
+			Threshold: threshold,
+		}))
+		return
+	}
+
+	fmt.Println("\nValidation Results:")
+	for _, result := range results {
+		if !quiet || result.Status == model.StatusFail {
+			printResult(result)
+		}
+	}
+}
+
+// colorEnabled reports whether stdout is a terminal and NO_COLOR is unset
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printResult prints a file's status followed by one line per finding
+func printResult(result model.FileResult) {
+	if result.Error != "" {
+		fmt.Printf("%s: %s (%s)\n", result.File, result.Status, result.Error)
+	} else {
+		fmt.Printf("%s: %s\n", result.File, result.Status)
+	}
+	for _, finding := range result.Findings {
+		fmt.Printf("  - %s\n", formatFinding(result.File, finding))
+	}
+}
+
+// formatFinding renders a finding as "[rule-id] file line N: message (severity)",
+// omitting the file when it is the one being reported and the line when unknown.
+// Suppressed findings are marked with their justification.
+func formatFinding(file string, finding model.Finding) string {
+	location := ""
+	if finding.File != "" && finding.File != file {
+		location = finding.File + " "
+	}
+	if finding.Line > 0 {
+		location += fmt.Sprintf("line %d", finding.Line)
+	}
+	if location != "" {
+		location = strings.TrimSpace(location) + ": "
+	}
+	formatted := fmt.Sprintf("[%s] %s%s (%s)", finding.RuleID, location, finding.Message, finding.Severity)
+	if finding.Suppressed {
+		formatted += fmt.Sprintf(" [suppressed: %s]", finding.Justification)
+	}
+	return formatted
+}
+
 func init() {
 	// Register flags
 	validateCmd.Flags().BoolVar(&strictMode, "strict", false, "Enable strict mode for validation")
 	validateCmd.Flags().StringVarP(&rulesPath, "rules", "r", "", "Path to custom compliance rules file")
+	validateCmd.Flags().IntVarP(&jobs, "jobs", "j", concurrency.DefaultJobs(), "Number of files to validate in parallel")
+	validateCmd.Flags().BoolVar(&noCache, "no-cache", false, "Re-validate every file instead of reusing cached results")
+	addHelmFlags(validateCmd)
+	addChangeFlags(validateCmd)
+	addBaselineFlag(validateCmd)
+	addFailOnFlag(validateCmd)
+	validateCmd.Flags().StringVar(&outputStyle, "output-style", "table", "How results are printed: table (grouped by file, coloured on a terminal unless NO_COLOR is set) or plain (one line per finding)")
+	validateCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only print failing files and the findings that fail them")
+	validateCmd.Flags().BoolVar(&changedLinesOnly, "changed-lines-only", false, "Only report findings on lines added or modified since --changed-since or in --staged changes")
 
 	// Register the validate command
 	rootCmd.AddCommand(validateCmd)
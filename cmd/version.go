@@ -5,11 +5,12 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/mtyiska/scanrunner/pkg/scanrunner"
 	"github.com/spf13/cobra"
 )
 
-// Define the version of the CLI tool
-const version = "v1.0.0"
+// Define the version of the CLI tool, shared with the public Go API
+const version = scanrunner.Version
 
 // versionCmd represents the "version" subcommand
 var versionCmd = &cobra.Command{
@@ -0,0 +1,220 @@
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// DefaultFile is the baseline file name used when none is given
+const DefaultFile = ".scanrunner-baseline.json"
+
+// formatVersion is the version of the baseline file format
+const formatVersion = 1
+
+// arrayIndex matches concrete array indices in field paths
+var arrayIndex = regexp.MustCompile(`\[\d+\]`)
+
+// Entry is one accepted finding
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	RuleID      string `json:"rule_id"`
+	File        string `json:"file"`
+	Resource    string `json:"resource,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Snippet     string `json:"snippet,omitempty"`
+	Message     string `json:"message,omitempty"` // Informational; not part of the fingerprint
+}
+
+// Baseline is the set of findings accepted as pre-existing
+type Baseline struct {
+	Version  int     `json:"version"`
+	Findings []Entry `json:"findings"`
+
+	index map[string]bool
+}
+
+// Fingerprint identifies a finding independently of line numbers, so it
+// survives unrelated edits: rule ID, file relative to root, resource, field
+// path with array indices normalised ("containers[2]" becomes "containers[]")
+// and, when set, the snippet with whitespace collapsed.
+func Fingerprint(finding model.Finding, root string) string {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%s",
+		finding.RuleID, model.RelativePath(finding.File, root), finding.Resource, NormalizePath(finding.Path))
+	// Findings without a snippet keep the fingerprints of earlier versions
+	if snippet := normalizeSnippet(finding.Snippet); snippet != "" {
+		key += "\x00" + snippet
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeSnippet collapses runs of whitespace so reformatting a line does
+// not change its fingerprint
+func normalizeSnippet(snippet string) string {
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
+// NormalizePath replaces concrete array indices in a field path with "[]"
+func NormalizePath(path string) string {
+	return arrayIndex.ReplaceAllString(path, "[]")
+}
+
+// New builds a baseline accepting every finding in results that is not
+// already suppressed
+func New(results []model.FileResult, root string) *Baseline {
+	b := &Baseline{Version: formatVersion, index: make(map[string]bool)}
+	for _, finding := range findingsOf(results) {
+		if finding.Suppressed {
+			continue
+		}
+		b.add(finding, root)
+	}
+	b.sort()
+	return b
+}
+
+// Load reads a baseline file. A missing file yields a nil baseline and no error.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if b.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s", b.Version, path)
+	}
+	b.index = make(map[string]bool)
+	for _, entry := range b.Findings {
+		b.index[entry.Fingerprint] = true
+	}
+	return &b, nil
+}
+
+// Save writes the baseline as indented JSON
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Contains reports whether a finding is accepted by the baseline
+func (b *Baseline) Contains(finding model.Finding, root string) bool {
+	return b != nil && b.index[Fingerprint(finding, root)]
+}
+
+// Filter hides baselined findings and re-derives each file's status. It
+// returns the filtered results and the number of findings hidden.
+func (b *Baseline) Filter(results []model.FileResult, root string) ([]model.FileResult, int) {
+	if b == nil {
+		return results, 0
+	}
+
+	hidden := 0
+	filtered := make([]model.FileResult, 0, len(results))
+	for _, result := range results {
+		var findings []model.Finding
+		for _, finding := range result.Findings {
+			if b.Contains(withFile(finding, result.File), root) {
+				hidden++
+				continue
+			}
+			findings = append(findings, finding)
+		}
+		result.Findings = findings
+		if result.Status == model.StatusFail && result.Error == "" && !model.Exceeds(findings, model.DefaultThreshold) {
+			result.Status = model.StatusPass
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered, hidden
+}
+
+// Prune drops entries that no longer match any finding in results and returns
+// how many were removed
+func (b *Baseline) Prune(results []model.FileResult, root string) int {
+	current := make(map[string]bool)
+	for _, finding := range findingsOf(results) {
+		current[Fingerprint(finding, root)] = true
+	}
+
+	kept := b.Findings[:0]
+	for _, entry := range b.Findings {
+		if current[entry.Fingerprint] {
+			kept = append(kept, entry)
+		} else {
+			delete(b.index, entry.Fingerprint)
+		}
+	}
+	removed := len(b.Findings) - len(kept)
+	b.Findings = kept
+	return removed
+}
+
+// add records a finding unless an entry with the same fingerprint exists
+func (b *Baseline) add(finding model.Finding, root string) {
+	fingerprint := Fingerprint(finding, root)
+	if b.index[fingerprint] {
+		return
+	}
+	b.index[fingerprint] = true
+	b.Findings = append(b.Findings, Entry{
+		Fingerprint: fingerprint,
+		RuleID:      finding.RuleID,
+		File:        model.RelativePath(finding.File, root),
+		Resource:    finding.Resource,
+		Path:        NormalizePath(finding.Path),
+		Snippet:     normalizeSnippet(finding.Snippet),
+		Message:     finding.Message,
+	})
+}
+
+// sort orders entries by file, rule and fingerprint for stable diffs
+func (b *Baseline) sort() {
+	sort.Slice(b.Findings, func(i, j int) bool {
+		x, y := b.Findings[i], b.Findings[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.RuleID != y.RuleID {
+			return x.RuleID < y.RuleID
+		}
+		return x.Fingerprint < y.Fingerprint
+	})
+}
+
+// findingsOf flattens the findings of every result, defaulting each
+// finding's file to the result's file
+func findingsOf(results []model.FileResult) []model.Finding {
+	var findings []model.Finding
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			findings = append(findings, withFile(finding, result.File))
+		}
+	}
+	return findings
+}
+
+// withFile fills in a finding's file when the validator left it empty
+func withFile(finding model.Finding, file string) model.Finding {
+	if finding.File == "" {
+		finding.File = file
+	}
+	return finding
+}
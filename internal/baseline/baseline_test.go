@@ -0,0 +1,118 @@
+package baseline_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mtyiska/scanrunner/internal/baseline"
+	"github.com/mtyiska/scanrunner/internal/compose"
+	"github.com/mtyiska/scanrunner/internal/docker"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/terraform"
+	"github.com/mtyiska/scanrunner/internal/workflow"
+)
+
+// TestFingerprintsDistinguishFindings checks that findings of one rule on the
+// same resource and path get distinct fingerprints when they concern
+// different instructions, actions or keys
+func TestFingerprintsDistinguishFindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings func(t *testing.T) []model.Finding
+	}{
+		{
+			name: "dockerfile instructions",
+			findings: func(t *testing.T) []model.Finding {
+				findings, err := docker.LintDockerfile("Dockerfile", []byte(`FROM golang:latest AS build
+ADD app.tar.gz /src/
+ADD https://example.com/tool /usr/bin/tool
+RUN apt-get install -y curl
+FROM alpine:latest
+RUN apt-get install -y ca-certificates
+`))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return findings
+			},
+		},
+		{
+			name: "workflow steps in one job",
+			findings: func(t *testing.T) []model.Finding {
+				return validate(t, workflow.Validator{}, ".github/workflows/ci.yaml", `on: pull_request
+permissions: read-all
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+      - run: echo "${{ github.event.pull_request.title }}" "${{ github.head_ref }}"
+`)
+			},
+		},
+		{
+			name: "compose environment keys",
+			findings: func(t *testing.T) []model.Finding {
+				return validate(t, compose.Validator{}, "compose.yaml", `services:
+  db:
+    image: postgres:16
+    environment:
+      POSTGRES_PASSWORD: hunter2
+      API_TOKEN: abc123
+`)
+			},
+		},
+		{
+			name: "terraform attributes",
+			findings: func(t *testing.T) []model.Finding {
+				return validate(t, terraform.Validator{}, "main.tf", `resource "aws_s3_bucket_public_access_block" "logs" {
+  bucket                  = "logs"
+  block_public_acls       = false
+  block_public_policy     = false
+  ignore_public_acls      = false
+  restrict_public_buckets = false
+}
+`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := tt.findings(t)
+			seen := make(map[string]model.Finding)
+			for _, finding := range findings {
+				fingerprint := baseline.Fingerprint(finding, ".")
+				if previous, ok := seen[fingerprint]; ok {
+					t.Errorf("%s findings collide:\n  %s\n  %s", finding.RuleID, previous.Message, finding.Message)
+				}
+				seen[fingerprint] = finding
+			}
+			if len(seen) < 2 {
+				t.Fatalf("got %d findings, want several of one rule", len(findings))
+			}
+		})
+	}
+}
+
+func TestFingerprintIgnoresLineAndWhitespace(t *testing.T) {
+	finding := model.Finding{RuleID: "DL3020", File: "Dockerfile", Line: 3, Snippet: "ADD app.tar.gz /src/"}
+	moved := finding
+	moved.Line = 7
+	moved.Snippet = "ADD  app.tar.gz\t/src/"
+	if baseline.Fingerprint(finding, ".") != baseline.Fingerprint(moved, ".") {
+		t.Error("fingerprint changed with the line or whitespace of the snippet")
+	}
+}
+
+func validate(t *testing.T, validator interface {
+	Validate(context.Context, string, []byte, model.Rules) ([]model.Finding, error)
+}, path, content string) []model.Finding {
+	t.Helper()
+	findings, err := validator.Validate(context.Background(), path, []byte(content), model.Rules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return findings
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// Cache stores validation findings on disk, keyed by the content of the
+// validated file and its dependencies, the rules and the scanrunner version.
+// Entries are written atomically, so concurrent runs can share a directory.
+type Cache struct {
+	dir  string
+	salt string // Hash of the rules and tool version shared by every key
+}
+
+// entryFormat is folded into every key and must change whenever the shape or
+// meaning of cached findings changes, so stale entries are never served
+const entryFormat = "10"
+
+var (
+	// shardName matches the directories entries are sharded into
+	shardName = regexp.MustCompile(`^[0-9a-f]{2}$`)
+	// entryName matches entry files and the temporary files Put renames into place
+	entryName = regexp.MustCompile(`^([0-9a-f]{64}\.json|\.tmp-\d+)$`)
+)
+
+// entry is the on-disk representation of a cached result
+type entry struct {
+	Validator string          `json:"validator"`
+	Findings  []model.Finding `json:"findings,omitempty"`
+}
+
+// DefaultDir returns the per-user cache directory for scanrunner
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "scanrunner")
+}
+
+// New returns a cache rooted at dir. Changing the rules or the version
+// invalidates every entry.
+func New(dir, version string, rules model.Rules) (*Cache, error) {
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash rules: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(entryFormat+"\x00"+version+"\x00"), rulesJSON...))
+	return &Cache{dir: dir, salt: hex.EncodeToString(sum[:])}, nil
+}
+
+// Key computes the cache key for a file validated by the named validator.
+// Dependencies are other files or directories the findings depend on; a
+// directory contributes the names of its entries, so validators list every
+// directory whose contents matter.
+func (c *Cache) Key(validator, filePath string, content []byte, dependencies []string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", c.salt, validator, filePath)
+	h.Write(content)
+	for _, dependency := range dependencies {
+		fmt.Fprintf(h, "\x00%s\x00", dependency)
+		hashDependency(h, dependency)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached findings for key
+func (c *Cache) Get(key string) (string, []model.Finding, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return "", nil, false // Treat corrupt entries as misses; they are overwritten on Put
+	}
+	return e.Validator, e.Findings, true
+}
+
+// Put stores findings under key. The entry is written to a temporary file and
+// renamed into place so readers never observe a partial write.
+func (c *Cache) Put(key, validator string, findings []model.Finding) error {
+	data, err := json.Marshal(entry{Validator: validator, Findings: findings})
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Clean removes every entry under dir. Only the cache's own shard
+// directories and entry files are removed, so pointing the cache at a
+// directory holding other files never deletes them. A missing dir is not an
+// error.
+func Clean(dir string) error {
+	shards, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, shard := range shards {
+		if !shard.IsDir() || !shardName.MatchString(shard.Name()) {
+			continue
+		}
+		shardDir := filepath.Join(dir, shard.Name())
+		entries, err := os.ReadDir(shardDir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() && entryName.MatchString(e.Name()) {
+				if err := os.Remove(filepath.Join(shardDir, e.Name())); err != nil {
+					return err
+				}
+			}
+		}
+		// Shards holding anything else are left in place
+		if err := os.Remove(shardDir); err != nil && !isNotEmpty(shardDir) {
+			return err
+		}
+	}
+	return nil
+}
+
+// isNotEmpty reports whether dir still has entries
+func isNotEmpty(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// path shards entries by the first two characters of the key
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// hashDependency writes a file's content, or the sorted names of a
+// directory's entries, to h
+func hashDependency(h io.Writer, path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		h.Write([]byte("missing"))
+		return
+	}
+	if !info.IsDir() {
+		if content, err := os.ReadFile(path); err == nil {
+			h.Write(content)
+		}
+		return
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		h.Write([]byte("unreadable"))
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h.Write([]byte(name + "\n"))
+	}
+}
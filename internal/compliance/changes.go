@@ -0,0 +1,49 @@
+package compliance
+
+import (
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/vcs"
+)
+
+// filterChanged keeps the results whose file is part of the change set
+func filterChanged(results []model.FileResult, changes *vcs.ChangeSet) []model.FileResult {
+	var filtered []model.FileResult
+	for _, result := range results {
+		if changes.Contains(result.File) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// FilterChangedLines drops the findings that do not fall on lines added or
+// modified by the change set, then re-derives each file's status. Findings
+// without a line number are kept for files with any changed line, since they
+// cannot be placed more precisely.
+func FilterChangedLines(results []model.FileResult, changes *vcs.ChangeSet) []model.FileResult {
+	if changes == nil {
+		return results
+	}
+
+	filtered := make([]model.FileResult, 0, len(results))
+	for _, result := range results {
+		var findings []model.Finding
+		for _, finding := range result.Findings {
+			file := finding.File
+			if file == "" {
+				file = result.File
+			}
+			if changes.ContainsLine(file, finding.Line) {
+				findings = append(findings, finding)
+			}
+		}
+		result.Findings = findings
+
+		// Results that could not be validated keep their failure
+		if result.Error == "" && result.Status != model.StatusSkipped {
+			result = withFindings(result, findings)
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
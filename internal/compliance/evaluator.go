@@ -1,39 +1,115 @@
 package compliance
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/mtyiska/scanrunner/internal/docker"
+	"github.com/mtyiska/scanrunner/internal/cache"
+	"github.com/mtyiska/scanrunner/internal/concurrency"
 	"github.com/mtyiska/scanrunner/internal/fileparser"
-	"github.com/mtyiska/scanrunner/internal/kubernetes"
 	"github.com/mtyiska/scanrunner/internal/model"
 )
 
+// ValidateFiles validates files concurrently with at most jobs workers and
+// returns the results in the same order as files. Files are classified with
+// validators, or the default registry when it is nil. Findings for unchanged files
+// are served from resultCache when it is not nil. When ctx is cancelled,
+// in-flight validations are stopped, files that never started are omitted and
+// ctx.Err() is returned.
+func ValidateFiles(ctx context.Context, files []string, rules model.Rules, jobs int, resultCache *cache.Cache, validators *Registry) ([]model.FileResult, error) {
+	if validators == nil {
+		validators = registry
+	}
+	results, err := concurrency.Map(ctx, files, jobs, func(ctx context.Context, file string) model.FileResult {
+		return validateFile(ctx, file, rules, resultCache, validators)
+	})
+
+	// Drop the zero results of files that were never started
+	completed := results[:0]
+	for _, result := range results {
+		if result.File != "" {
+			completed = append(completed, result)
+		}
+	}
+	return completed, err
+}
 
-func ValidateFile(filePath string, rules model.Rules) error {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	fileName := strings.ToLower(filepath.Base(filePath))
+// ValidateFile classifies a file with the validator registry and validates it
+// with the validator that claims it. Files no validator claims are skipped.
+func ValidateFile(ctx context.Context, filePath string, rules model.Rules) model.FileResult {
+	return validateFile(ctx, filePath, rules, nil, registry)
+}
 
-	switch {
-	case ext == ".yaml" || ext == ".yml":
-		parsedData, err := fileparser.ParseAndConvertYAML(filePath)
-		if err != nil {
-			return fmt.Errorf("error parsing YAML file: %w", err)
+// validateFile implements ValidateFile with the given validators, consulting
+// resultCache when it is not nil
+func validateFile(ctx context.Context, filePath string, rules model.Rules, resultCache *cache.Cache, validators *Registry) model.FileResult {
+	result := model.FileResult{File: filePath}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		result.Status = model.StatusFail
+		result.Error = fmt.Sprintf("failed to read file: %v", err)
+		return result
+	}
+
+	validator := validators.Detect(filePath, content)
+	if validator == nil {
+		// Surface broken YAML instead of silently skipping it
+		ext := strings.ToLower(filepath.Ext(filePath))
+		if ext == ".yaml" || ext == ".yml" {
+			if _, err := fileparser.ParseYAMLContent(content); err != nil {
+				result.Status = model.StatusFail
+				result.Error = fmt.Sprintf("error parsing YAML file: %v", err)
+				result.InvalidInput = true
+				return result
+			}
 		}
-		if err := kubernetes.ValidateKubernetesManifest(parsedData, rules); err != nil {
-			return fmt.Errorf("Kubernetes manifest validation failed: %w", err)
+		result.Status = model.StatusSkipped
+		result.Error = "no validator recognises this file"
+		return result
+	}
+
+	result.Validator = validator.Name()
+
+	var cacheKey string
+	if resultCache != nil {
+		var dependencies []string
+		if reporter, ok := validator.(DependencyReporter); ok {
+			dependencies = reporter.Dependencies(filePath, content)
 		}
-		return nil
+		cacheKey = resultCache.Key(validator.Name(), filePath, content, dependencies)
+		if name, findings, ok := resultCache.Get(cacheKey); ok && name == validator.Name() {
+			return withFindings(result, findings)
+		}
+	}
 
-	case strings.Contains(fileName, "docker"): // Handle Docker-related files
-		if err := docker.ValidateDockerfile(filePath); err != nil {
-			return fmt.Errorf("Dockerfile validation failed: %w", err)
+	findings, err := validator.Validate(ctx, filePath, content, rules)
+	if err != nil {
+		result.Status = model.StatusFail
+		result.Error = fmt.Sprintf("%s validation failed: %v", validator.Name(), err)
+		result.InvalidInput = model.IsInputError(err)
+		return result
+	}
+
+	// Only complete validations are cached; errors such as a missing Trivy are retried next run
+	if resultCache != nil {
+		if err := resultCache.Put(cacheKey, validator.Name(), findings); err != nil {
+			log.Printf("Failed to write cache entry for %s: %v\n", filePath, err)
 		}
-		return nil
+	}
+	return withFindings(result, findings)
+}
 
-	default:
-		return fmt.Errorf("unsupported file type: %s. Supported types are: .yaml, .yml, and Docker-related files", filePath)
+// withFindings sets the findings on a result and derives its status from them
+func withFindings(result model.FileResult, findings []model.Finding) model.FileResult {
+	result.Findings = findings
+	result.Status = model.StatusPass
+	if model.Exceeds(findings, model.DefaultThreshold) {
+		result.Status = model.StatusFail
 	}
-}
\ No newline at end of file
+	return result
+}
@@ -0,0 +1,131 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/concurrency"
+	"github.com/mtyiska/scanrunner/internal/fileparser"
+	"github.com/mtyiska/scanrunner/internal/helm"
+	"github.com/mtyiska/scanrunner/internal/kubernetes"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/vcs"
+)
+
+// ValidateHelmCharts renders every Helm chart found among files and validates
+// the rendered manifests, reporting one result per template. It returns the
+// files that lie outside any chart, which still need per-file validation.
+//
+// When changes is not nil, charts without changed files are not rendered. For
+// the others, a changed values file, helper or Chart.yaml reports every
+// template, while changes limited to templates report only those templates.
+//
+// Charts are rendered concurrently with at most jobs workers. When ctx is
+// cancelled, no further charts are rendered and ctx.Err() is returned.
+func ValidateHelmCharts(ctx context.Context, files []string, opts helm.ValuesOptions, rules model.Rules, changes *vcs.ChangeSet, jobs int) ([]string, []model.FileResult, error) {
+	charts := helm.FindCharts(files)
+	if len(charts) == 0 {
+		return files, nil, nil
+	}
+
+	var remaining []string
+	for _, file := range files {
+		inChart := false
+		for _, chart := range charts {
+			if helm.InChart(file, chart) {
+				inChart = true
+				break
+			}
+		}
+		if !inChart {
+			remaining = append(remaining, file)
+		}
+	}
+
+	var changed []string
+	for _, chart := range charts {
+		if changes.ContainsUnder(chart) {
+			changed = append(changed, chart)
+		}
+	}
+	chartResults, err := concurrency.Map(ctx, changed, jobs, func(ctx context.Context, chart string) []model.FileResult {
+		results := validateHelmChart(chart, opts, rules)
+		if changes != nil && !chartInputsChanged(chart, changes) {
+			results = filterChanged(results, changes)
+		}
+		return results
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []model.FileResult
+	for _, r := range chartResults {
+		results = append(results, r...)
+	}
+	return remaining, results, nil
+}
+
+// chartInputsChanged reports whether a changed file in the chart feeds every
+// template: values files, Chart.yaml, helpers and anything else that is not a
+// template manifest
+func chartInputsChanged(chart string, changes *vcs.ChangeSet) bool {
+	templates := filepath.Join(chart, "templates")
+	for _, file := range changes.Under(chart) {
+		ext := strings.ToLower(filepath.Ext(file))
+		isManifest := ext == ".yaml" || ext == ".yml" || ext == ".json"
+		if !helm.InChart(file, templates) || !isManifest {
+			return true
+		}
+	}
+	return false
+}
+
+// validateHelmChart renders one chart and validates each rendered manifest,
+// attributing findings to the template that produced them
+func validateHelmChart(chartDir string, opts helm.ValuesOptions, rules model.Rules) []model.FileResult {
+	manifests, err := helm.RenderChart(chartDir, opts)
+	if err != nil {
+		return []model.FileResult{{
+			File:      filepath.Join(chartDir, helm.ChartFile),
+			Validator: "helm",
+			Status:    model.StatusFail,
+			Error:     err.Error(),
+		}}
+	}
+
+	templates := make([]string, 0, len(manifests))
+	for template := range manifests {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	var results []model.FileResult
+	for _, template := range templates {
+		result := model.FileResult{File: template, Validator: "helm", Status: model.StatusPass}
+
+		documents, err := fileparser.ParseYAMLDocuments([]byte(manifests[template]))
+		if err != nil {
+			result.Status = model.StatusFail
+			result.Error = fmt.Sprintf("rendered template is not valid YAML: %v", err)
+			result.InvalidInput = true
+			results = append(results, result)
+			continue
+		}
+		for _, document := range documents {
+			for _, finding := range kubernetes.ValidateKubernetesManifest(document, rules) {
+				finding.File = template
+				finding.Message = fmt.Sprintf("%s: %s", kubernetes.ResourceName(document), finding.Message)
+				result.Findings = append(result.Findings, finding)
+			}
+		}
+		if model.Exceeds(result.Findings, model.DefaultThreshold) {
+			result.Status = model.StatusFail
+		}
+		results = append(results, result)
+	}
+	return results
+}
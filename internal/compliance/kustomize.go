@@ -0,0 +1,126 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mtyiska/scanrunner/internal/concurrency"
+	"github.com/mtyiska/scanrunner/internal/kubernetes"
+	"github.com/mtyiska/scanrunner/internal/kustomize"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/vcs"
+)
+
+// ValidateKustomizations builds every Kustomize overlay found among files and
+// validates the resulting resources, reporting one result per overlay. It
+// returns the files that still need per-file validation: kustomization files
+// and the patch fragments they reference are excluded.
+//
+// When changes is not nil, only overlays that include a changed file, directly
+// or through the bases and components they reference, are built and reported.
+//
+// Overlays are built concurrently with at most jobs workers. When ctx is
+// cancelled, no further overlays are built and ctx.Err() is returned.
+func ValidateKustomizations(ctx context.Context, files []string, rules model.Rules, changes *vcs.ChangeSet, jobs int) ([]string, []model.FileResult, error) {
+	var kustomizations []kustomize.Kustomization
+	var results []model.FileResult
+	excluded := make(map[string]bool)
+
+	for _, file := range files {
+		if !kustomize.IsKustomizationFile(file) {
+			continue
+		}
+		excluded[filepath.Clean(file)] = true
+
+		k, err := kustomize.LoadKustomization(file)
+		if err != nil {
+			results = append(results, model.FileResult{
+				File:         file,
+				Validator:    "kustomize",
+				Status:       model.StatusFail,
+				Error:        err.Error(),
+				InvalidInput: model.IsInputError(err),
+			})
+			continue
+		}
+		for _, patch := range k.Patches {
+			excluded[filepath.Clean(patch)] = true
+		}
+		kustomizations = append(kustomizations, k)
+	}
+	if len(excluded) == 0 {
+		return files, nil, nil
+	}
+
+	var remaining []string
+	for _, file := range files {
+		if !excluded[filepath.Clean(file)] {
+			remaining = append(remaining, file)
+		}
+	}
+
+	var overlays []kustomize.Kustomization
+	for _, overlay := range kustomize.FindOverlays(kustomizations) {
+		if overlayChanged(overlay, kustomizations, changes) {
+			overlays = append(overlays, overlay)
+		}
+	}
+	overlayResults, err := concurrency.Map(ctx, overlays, jobs, func(ctx context.Context, overlay kustomize.Kustomization) model.FileResult {
+		return validateOverlay(overlay, rules)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return remaining, append(results, overlayResults...), nil
+}
+
+// overlayChanged reports whether any changed file lies in the overlay or in a
+// directory it references, following references transitively
+func overlayChanged(overlay kustomize.Kustomization, kustomizations []kustomize.Kustomization, changes *vcs.ChangeSet) bool {
+	byDir := make(map[string]kustomize.Kustomization)
+	for _, k := range kustomizations {
+		byDir[filepath.Clean(k.Dir)] = k
+	}
+
+	visited := make(map[string]bool)
+	pending := []string{overlay.Dir}
+	for len(pending) > 0 {
+		dir := filepath.Clean(pending[0])
+		pending = pending[1:]
+		if visited[dir] {
+			continue
+		}
+		visited[dir] = true
+		if changes.ContainsUnder(dir) {
+			return true
+		}
+		pending = append(pending, byDir[dir].References...)
+	}
+	return false
+}
+
+// validateOverlay builds one overlay and validates every resource it produces,
+// attributing findings to the overlay's kustomization file
+func validateOverlay(overlay kustomize.Kustomization, rules model.Rules) model.FileResult {
+	result := model.FileResult{File: overlay.File, Validator: "kustomize", Status: model.StatusPass}
+
+	resources, err := kustomize.Build(overlay.Dir)
+	if err != nil {
+		result.Status = model.StatusFail
+		result.Error = err.Error()
+		return result
+	}
+
+	for _, resource := range resources {
+		for _, finding := range kubernetes.ValidateKubernetesManifest(resource.Data, rules) {
+			finding.File = overlay.File
+			finding.Message = fmt.Sprintf("overlay %s: %s: %s", overlay.Dir, resource.ID, finding.Message)
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+	if model.Exceeds(result.Findings, model.DefaultThreshold) {
+		result.Status = model.StatusFail
+	}
+	return result
+}
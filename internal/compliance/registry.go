@@ -0,0 +1,87 @@
+package compliance
+
+import (
+	"context"
+
+	"github.com/mtyiska/scanrunner/internal/compose"
+	"github.com/mtyiska/scanrunner/internal/docker"
+	"github.com/mtyiska/scanrunner/internal/kubernetes"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/terraform"
+	"github.com/mtyiska/scanrunner/internal/workflow"
+)
+
+// Validator is implemented by every file type scanrunner can validate.
+// Detect classifies a file from its path and content; Validate returns the
+// findings for a file it claimed, or an error if the file could not be
+// validated at all. Validate should stop early when ctx is cancelled.
+type Validator interface {
+	Name() string
+	Detect(filePath string, content []byte) bool
+	Validate(ctx context.Context, filePath string, content []byte, rules model.Rules) ([]model.Finding, error)
+}
+
+// DependencyReporter is implemented by validators whose findings also depend
+// on paths other than the validated file. Those paths are folded into the
+// result cache key so edits to them invalidate cached findings.
+type DependencyReporter interface {
+	Dependencies(filePath string, content []byte) []string
+}
+
+// Registry holds validators in priority order. Validators registered earlier
+// take precedence when several claim the same file.
+type Registry struct {
+	validators []Validator
+}
+
+// NewRegistry returns a registry of the built-in validators. Files whose
+// name matches one of dockerfilePatterns are claimed as Dockerfiles.
+func NewRegistry(dockerfilePatterns []string) *Registry {
+	// Workflows and Compose are checked before Kubernetes so that path- and
+	// name-based detection wins
+	r := &Registry{}
+	r.Register(workflow.Validator{})
+	r.Register(compose.Validator{})
+	r.Register(kubernetes.Validator{})
+	r.Register(docker.Validator{Patterns: dockerfilePatterns})
+	r.Register(terraform.Validator{})
+	return r
+}
+
+// Register adds a validator after those already in the registry
+func (r *Registry) Register(v Validator) {
+	r.validators = append(r.validators, v)
+}
+
+// Validators returns the registered validators in priority order
+func (r *Registry) Validators() []Validator {
+	return append([]Validator(nil), r.validators...)
+}
+
+// Detect returns the first registered validator that claims the file, or nil
+func (r *Registry) Detect(filePath string, content []byte) Validator {
+	for _, v := range r.validators {
+		if v.Detect(filePath, content) {
+			return v
+		}
+	}
+	return nil
+}
+
+// registry is the default registry, without extra Dockerfile patterns
+var registry = NewRegistry(nil)
+
+// Register adds a validator to the default registry
+func Register(v Validator) {
+	registry.Register(v)
+}
+
+// Validators returns the validators of the default registry in priority order
+func Validators() []Validator {
+	return registry.Validators()
+}
+
+// Detect returns the first validator of the default registry that claims the file, or nil
+func Detect(filePath string, content []byte) Validator {
+	return registry.Detect(filePath, content)
+}
@@ -0,0 +1,295 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/docker"
+	"github.com/mtyiska/scanrunner/internal/fileparser"
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// composeFileName matches the file names Docker Compose looks for by default,
+// including override and environment-specific variants.
+var composeFileName = regexp.MustCompile(`^(docker-)?compose([.-][\w.-]+)?\.ya?ml$`)
+
+// secretKey matches environment variable names that usually hold credentials.
+var secretKey = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_?KEY|ACCESS_?KEY|CREDENTIALS?)`)
+
+// Validator validates Docker Compose files.
+type Validator struct{}
+
+// Name returns the validator name used in results
+func (Validator) Name() string { return "compose" }
+
+// Detect reports whether the file is a Compose file
+func (Validator) Detect(filePath string, content []byte) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext != ".yaml" && ext != ".yml" {
+		return false
+	}
+	parsedData, err := fileparser.ParseYAMLContent(content)
+	if err != nil {
+		return false
+	}
+	return IsComposeFile(filePath, parsedData)
+}
+
+// Validate parses the Compose file and returns its findings
+func (Validator) Validate(ctx context.Context, filePath string, content []byte, rules model.Rules) ([]model.Finding, error) {
+	parsedData, err := fileparser.ParseYAMLContent(content)
+	if err != nil {
+		return nil, &model.InputError{Err: fmt.Errorf("error parsing YAML file: %w", err)}
+	}
+	findings, err := ValidateComposeFile(filePath, parsedData)
+	for i := range findings {
+		if findings[i].File == filePath {
+			findings[i].Line = fileparser.LocateLine(content, findings[i].Path)
+		}
+	}
+	return findings, err
+}
+
+// Dependencies returns the Dockerfiles referenced by build sections, since
+// their lint findings are part of the result
+func (Validator) Dependencies(filePath string, content []byte) []string {
+	parsedData, err := fileparser.ParseYAMLContent(content)
+	if err != nil {
+		return nil
+	}
+	services, _ := parsedData["services"].(map[string]interface{})
+
+	var dependencies []string
+	for _, service := range services {
+		serviceMap, ok := service.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dockerfilePath, ok := buildDockerfile(filePath, serviceMap["build"])
+		if !ok {
+			continue
+		}
+		dependencies = append(dependencies, dockerfilePath)
+	}
+	sort.Strings(dependencies)
+	return dependencies
+}
+
+// IsComposeFile reports whether a parsed YAML file is a Compose file, either
+// by its name or by a top-level "services" map. Files with Kubernetes markers
+// are never Compose files, whatever their name.
+func IsComposeFile(filePath string, data map[string]interface{}) bool {
+	if _, ok := data["apiVersion"]; ok {
+		return false
+	}
+	if _, ok := data["kind"]; ok {
+		return false
+	}
+	if composeFileName.MatchString(strings.ToLower(filepath.Base(filePath))) {
+		return true
+	}
+	_, ok := data["services"].(map[string]interface{})
+	return ok
+}
+
+// ValidateComposeFile validates a parsed Compose file for security and
+// operational best practices.
+func ValidateComposeFile(filePath string, data map[string]interface{}) ([]model.Finding, error) {
+	services, ok := data["services"].(map[string]interface{})
+	if !ok {
+		return nil, &model.InputError{Err: errors.New("missing or invalid 'services' section")}
+	}
+
+	// Validate services in a stable order so repeated runs report the same findings
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var findings []model.Finding
+	for _, name := range names {
+		service, ok := services[name].(map[string]interface{})
+		if !ok {
+			return nil, &model.InputError{Err: fmt.Errorf("service '%s' is not a valid object", name)}
+		}
+		for _, finding := range validateService(filePath, service) {
+			if finding.File == "" {
+				finding.File = filePath
+				finding.Resource = "service/" + name
+				finding.Path = strings.TrimSuffix("services."+name+"."+finding.Path, ".")
+				finding.Message = fmt.Sprintf("service '%s': %s", name, finding.Message)
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// validateService runs the per-service checks.
+func validateService(filePath string, service map[string]interface{}) []model.Finding {
+	var findings []model.Finding
+	add := func(ruleID, severity, field, message string) {
+		findings = append(findings, model.Finding{RuleID: ruleID, Severity: severity, Path: field, Message: message})
+	}
+
+	if privileged, _ := service["privileged"].(bool); privileged {
+		add("compose-privileged", model.SeverityCritical, "privileged", "privileged mode grants full host access; drop 'privileged: true'")
+	}
+	if mode, _ := service["network_mode"].(string); mode == "host" {
+		add("compose-host-network", model.SeverityHigh, "network_mode", "'network_mode: host' shares the host network namespace")
+	}
+	if pid, _ := service["pid"].(string); pid == "host" {
+		add("compose-host-pid", model.SeverityHigh, "pid", "'pid: host' shares the host process namespace")
+	}
+	if source := dockerSocketMount(service["volumes"]); source != "" {
+		add("compose-docker-socket", model.SeverityCritical, "volumes", fmt.Sprintf("mounting %s gives the container control of the Docker daemon", source))
+	}
+	if image, ok := service["image"].(string); ok {
+		if message := checkImage(image); message != "" {
+			add("compose-image-tag", model.SeverityMedium, "image", message)
+		}
+	}
+	for _, key := range hardCodedSecrets(service["environment"]) {
+		add("compose-env-secret", model.SeverityHigh, "environment."+key, fmt.Sprintf("environment variable '%s' contains a hard-coded secret; use Compose secrets or variable interpolation", key))
+	}
+	if _, exists := service["healthcheck"]; !exists {
+		add("compose-healthcheck", model.SeverityInfo, "", "no healthcheck defined. Consider adding one so dependants can wait for readiness.")
+	}
+
+	return append(findings, validateBuild(filePath, service["build"])...)
+}
+
+// dockerSocketMount returns the source of a Docker daemon socket mount, in
+// either the short "src:dst" or the long {source, target} syntax.
+func dockerSocketMount(volumes interface{}) string {
+	list, ok := volumes.([]interface{})
+	if !ok {
+		return ""
+	}
+	for _, volume := range list {
+		var source string
+		switch v := volume.(type) {
+		case string:
+			source = strings.SplitN(v, ":", 2)[0]
+		case map[string]interface{}:
+			source, _ = v["source"].(string)
+		}
+		if strings.HasSuffix(source, "docker.sock") {
+			return source
+		}
+	}
+	return ""
+}
+
+// checkImage requires an explicit, non-latest tag or a digest.
+func checkImage(image string) string {
+	if strings.Contains(image, "@") {
+		return "" // Pinned by digest
+	}
+	lastSegment := image[strings.LastIndex(image, "/")+1:]
+	if !strings.Contains(lastSegment, ":") {
+		return fmt.Sprintf("image '%s' has no tag; pin a specific version", image)
+	}
+	if strings.HasSuffix(lastSegment, ":latest") {
+		return fmt.Sprintf("image '%s' uses the 'latest' tag; pin a specific version", image)
+	}
+	return ""
+}
+
+// hardCodedSecrets returns the credential-like variables set as literal
+// values. Values that interpolate from the shell ("${DB_PASSWORD}") are allowed.
+func hardCodedSecrets(environment interface{}) []string {
+	vars := make(map[string]string)
+	switch env := environment.(type) {
+	case map[string]interface{}:
+		for key, value := range env {
+			if value != nil {
+				vars[key] = fmt.Sprintf("%v", value)
+			}
+		}
+	case []interface{}:
+		for _, entry := range env {
+			if pair, ok := entry.(string); ok {
+				if parts := strings.SplitN(pair, "=", 2); len(parts) == 2 {
+					vars[parts[0]] = parts[1]
+				}
+			}
+		}
+	}
+
+	var keys []string
+	for key, value := range vars {
+		if secretKey.MatchString(key) && value != "" && !strings.HasPrefix(value, "$") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateBuild lints the Dockerfile referenced by a service's build section.
+// Only the lint checks run: the Trivy scan and build context checks belong to
+// the Dockerfile's own validation. A Dockerfile that cannot be read or parsed
+// is reported as a finding, so the service's other findings are kept.
+func validateBuild(filePath string, build interface{}) []model.Finding {
+	dockerfilePath, ok := buildDockerfile(filePath, build)
+	if !ok {
+		return nil
+	}
+	buildFinding := func(message string) []model.Finding {
+		return []model.Finding{{
+			RuleID:   "compose-build-dockerfile",
+			Severity: model.SeverityMedium,
+			Path:     "build",
+			Message:  message,
+		}}
+	}
+	content, err := os.ReadFile(dockerfilePath)
+	if os.IsNotExist(err) {
+		return buildFinding(fmt.Sprintf("build Dockerfile %s not found", dockerfilePath))
+	}
+	if err != nil {
+		return buildFinding(fmt.Sprintf("build Dockerfile %s cannot be read: %v", dockerfilePath, err))
+	}
+	findings, err := docker.LintDockerfile(dockerfilePath, content)
+	if err != nil {
+		return buildFinding(fmt.Sprintf("build Dockerfile %s cannot be parsed: %v", dockerfilePath, err))
+	}
+	return findings
+}
+
+// buildDockerfile resolves the local Dockerfile a build section refers to. It
+// returns false when there is no build section, the Dockerfile is inline, or
+// the context is remote.
+func buildDockerfile(filePath string, build interface{}) (string, bool) {
+	contextDir, dockerfile := "", "Dockerfile"
+	switch b := build.(type) {
+	case string:
+		contextDir = b
+	case map[string]interface{}:
+		contextDir, _ = b["context"].(string)
+		if name, ok := b["dockerfile"].(string); ok && name != "" {
+			dockerfile = name
+		}
+		if _, inline := b["dockerfile_inline"]; inline {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	if strings.Contains(contextDir, "://") {
+		return "", false // Remote Git or URL contexts cannot be linted locally
+	}
+
+	if filepath.IsAbs(dockerfile) {
+		return dockerfile, true
+	}
+	return filepath.Join(filepath.Dir(filePath), contextDir, dockerfile), true
+}
@@ -0,0 +1,63 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+func TestValidateKeepsServiceFindingsWithBuild(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string // Empty for no Dockerfile
+		want       []string
+	}{
+		{
+			name:       "lint findings of the build Dockerfile",
+			dockerfile: "FROM alpine:latest\nADD app.tar.gz /app/\n",
+			want:       []string{"compose-privileged", "compose-healthcheck", "DL3020", "DL3007"},
+		},
+		{
+			name: "missing build Dockerfile",
+			want: []string{"compose-privileged", "compose-healthcheck", "compose-build-dockerfile"},
+		},
+		{
+			name:       "unparsable build Dockerfile",
+			dockerfile: "# no instructions\n",
+			want:       []string{"compose-privileged", "compose-healthcheck", "compose-build-dockerfile"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Trivy is never needed: build Dockerfiles are only linted
+			t.Setenv("PATH", "")
+			dir := t.TempDir()
+			if tt.dockerfile != "" {
+				if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(tt.dockerfile), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			path := filepath.Join(dir, "compose.yaml")
+			content := []byte("services:\n  web:\n    build: .\n    privileged: true\n")
+
+			findings, err := Validator{}.Validate(context.Background(), path, content, model.Rules{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, finding := range findings {
+				got = append(got, finding.RuleID)
+			}
+			slices.Sort(got)
+			slices.Sort(tt.want)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rule IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
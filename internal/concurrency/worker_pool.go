@@ -0,0 +1,55 @@
+package concurrency
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// DefaultJobs returns the default worker count, one per usable CPU
+func DefaultJobs() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// Map applies fn to every item using at most jobs concurrent workers and
+// returns the results in input order, regardless of completion order.
+//
+// When ctx is cancelled no new items are started; items already running are
+// expected to observe ctx themselves. Results for items that never started
+// keep their zero value and Map returns ctx.Err().
+func Map[T, R any](ctx context.Context, items []T, jobs int, fn func(context.Context, T) R) ([]R, error) {
+	if jobs < 1 {
+		jobs = DefaultJobs()
+	}
+	if jobs > len(items) {
+		jobs = len(items)
+	}
+
+	results := make([]R, len(items))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = fn(ctx, items[i])
+			}
+		}()
+	}
+
+	// Feed work until every item is dispatched or the context is cancelled
+feed:
+	for i := range items {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, ctx.Err()
+}
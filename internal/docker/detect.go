@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultPatterns lists the file name globs recognised as Dockerfiles.
+// Matching is case-insensitive and applies to the base name only.
+var DefaultPatterns = []string{
+	"Dockerfile",
+	"Dockerfile.*",
+	"*.Dockerfile",
+	"Containerfile",
+	"Containerfile.*",
+	"*.Containerfile",
+}
+
+// sniffLimit caps how much of a file is read when sniffing its content.
+const sniffLimit = 8 * 1024
+
+// firstInstruction matches the only instructions allowed to open a Dockerfile.
+var firstInstruction = regexp.MustCompile(`(?i)^(FROM|ARG)\s+\S`)
+
+// IsDockerfile reports whether filePath is a Dockerfile. The base name is
+// matched against DefaultPatterns plus any extra globs; when none match, the
+// file content is sniffed for a leading FROM or ARG instruction.
+func IsDockerfile(filePath string, extraPatterns ...string) bool {
+	if MatchesName(filepath.Base(filePath), extraPatterns...) {
+		return true
+	}
+	return sniffDockerfile(filePath)
+}
+
+// MatchesName reports whether a base file name matches DefaultPatterns or any
+// of the extra globs.
+func MatchesName(name string, extraPatterns ...string) bool {
+	name = strings.ToLower(name)
+	patterns := append(append([]string{}, DefaultPatterns...), extraPatterns...)
+	for _, pattern := range patterns {
+		if ok, err := filepath.Match(strings.ToLower(pattern), name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffDockerfile reads the head of a file and sniffs it with SniffContent.
+func sniffDockerfile(filePath string) bool {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	head, err := io.ReadAll(io.LimitReader(file, sniffLimit))
+	if err != nil {
+		return false
+	}
+	return SniffContent(head)
+}
+
+// SniffContent checks whether the first instruction in content, after blank
+// lines, comments and parser directives, is FROM or ARG.
+func SniffContent(content []byte) bool {
+	if len(content) > sniffLimit {
+		content = content[:sniffLimit]
+	}
+	if bytes.IndexByte(content, 0) >= 0 { // Skip binary files
+		return false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return firstInstruction.MatchString(line)
+	}
+	return false
+}
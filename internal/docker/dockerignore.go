@@ -0,0 +1,280 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/moby/patternmatcher"
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// SensitivePatterns lists build context paths that are either secret or heavy
+// and should normally be excluded through .dockerignore.
+var SensitivePatterns = []string{
+	".git",
+	".env",
+	".env.*",
+	"*.pem",
+	"*.key",
+	"id_rsa*",
+	".ssh",
+	".aws",
+	".npmrc",
+	"node_modules",
+	".terraform",
+}
+
+// LoadDockerignore reads the .dockerignore that applies to a Dockerfile and
+// returns its patterns. A Dockerfile-specific "<name>.dockerignore" takes
+// precedence over the context-wide ".dockerignore". The returned path is empty
+// when neither file exists.
+func LoadDockerignore(dockerfilePath string) ([]string, string, error) {
+	candidates := []string{
+		dockerfilePath + ".dockerignore",
+		filepath.Join(filepath.Dir(dockerfilePath), ".dockerignore"),
+	}
+	for _, candidate := range candidates {
+		file, err := os.Open(candidate)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, candidate, fmt.Errorf("failed to open %s: %w", candidate, err)
+		}
+		defer file.Close()
+
+		patterns, err := dockerignore.ReadAll(file)
+		if err != nil {
+			return nil, candidate, fmt.Errorf("failed to read %s: %w", candidate, err)
+		}
+		return patterns, candidate, nil
+	}
+	return nil, "", nil
+}
+
+// ValidateDockerignore checks that every pattern in a .dockerignore file is
+// syntactically valid.
+func ValidateDockerignore(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	patterns, err := dockerignore.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	for _, pattern := range patterns {
+		if _, err := patternmatcher.New([]string{pattern}); err != nil {
+			return fmt.Errorf("invalid pattern %q in %s: %w", pattern, path, err)
+		}
+	}
+	return nil
+}
+
+// ContextFiles walks the build context directory and returns the paths,
+// relative to contextDir, that survive the given .dockerignore patterns.
+// Directories excluded by the patterns, and directories matching a
+// SensitivePatterns entry, are not descended into: the latter are returned
+// themselves, without their contents.
+func ContextFiles(contextDir string, patterns []string) ([]string, error) {
+	var files []string
+	err := walkContext(contextDir, patterns, func(relPath string, isDir bool) {
+		files = append(files, relPath)
+	})
+	return files, err
+}
+
+// ContextDirs returns contextDir and the directories ContextFiles descends
+// into, as paths joined to contextDir. Listing each of them covers every path
+// ContextFiles returns, without walking pruned directories.
+func ContextDirs(contextDir string, patterns []string) ([]string, error) {
+	dirs := []string{contextDir}
+	err := walkContext(contextDir, patterns, func(relPath string, isDir bool) {
+		if isDir && !sensitiveName(path.Base(relPath)) {
+			dirs = append(dirs, filepath.Join(contextDir, filepath.FromSlash(relPath)))
+		}
+	})
+	return dirs, err
+}
+
+// walkContext calls visit with the slash-separated path of every file and
+// directory in the build context, pruning excluded and sensitive directories
+func walkContext(contextDir string, patterns []string, visit func(relPath string, isDir bool)) error {
+	matcher, err := patternmatcher.New(patterns)
+	if err != nil {
+		return fmt.Errorf("invalid .dockerignore patterns: %w", err)
+	}
+
+	return filepath.Walk(contextDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(contextDir, filePath)
+		if err != nil || relPath == "." {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		excluded, err := matcher.MatchesOrParentMatches(relPath)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			// Exclusion patterns ("!foo") may re-include children, so only
+			// prune directories none of them can reach into.
+			if info.IsDir() && !mayReinclude(matcher, relPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		visit(relPath, info.IsDir())
+		if info.IsDir() && sensitiveName(info.Name()) {
+			return filepath.SkipDir // Reported as a whole; its contents do not matter
+		}
+		return nil
+	})
+}
+
+// mayReinclude reports whether an exclusion pattern could re-include a path
+// under dir. Only the literal prefix of each pattern, up to its first
+// wildcard, is compared, so the answer errs on the side of walking.
+func mayReinclude(matcher *patternmatcher.PatternMatcher, dir string) bool {
+	for _, pattern := range matcher.Patterns() {
+		if !pattern.Exclusion() {
+			continue
+		}
+		literal := filepath.ToSlash(pattern.String())
+		if i := strings.IndexAny(literal, "*?[\\"); i >= 0 {
+			literal = literal[:i]
+		}
+		if strings.HasPrefix(literal, dir+"/") || strings.HasPrefix(dir+"/", literal) {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveName reports whether a file or directory name matches a
+// SensitivePatterns entry
+func sensitiveName(name string) bool {
+	for _, pattern := range SensitivePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBuildContext validates the applicable .dockerignore and, when a
+// Dockerfile copies its whole build context, warns about sensitive or heavy
+// paths that the context would include. The build context is assumed to be
+// the Dockerfile's directory, as with "docker build -f Dockerfile <dir>" run
+// from there; a context given elsewhere, e.g. in a Compose build section, is
+// not known here.
+func checkBuildContext(filePath string, ast *parser.Node) ([]model.Finding, error) {
+	copyLine := wholeContextCopyLine(ast)
+
+	patterns, ignorePath, err := LoadDockerignore(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if ignorePath != "" {
+		if err := ValidateDockerignore(ignorePath); err != nil {
+			return []model.Finding{{
+				RuleID:   "dockerignore-syntax",
+				Severity: model.SeverityMedium,
+				File:     ignorePath,
+				Message:  err.Error(),
+			}}, nil
+		}
+	}
+	if copyLine == 0 {
+		return nil, nil
+	}
+
+	files, err := ContextFiles(filepath.Dir(filePath), patterns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute build context: %w", err)
+	}
+	var findings []model.Finding
+	for _, found := range sensitiveContextPaths(files) {
+		findings = append(findings, model.Finding{
+			RuleID:   "dockerignore-sensitive-path",
+			Severity: model.SeverityMedium,
+			Line:     copyLine,
+			Snippet:  found,
+			Message:  fmt.Sprintf("build context includes %s. Consider adding it to .dockerignore.", found),
+		})
+	}
+	return findings, nil
+}
+
+// wholeContextCopyLine returns the line of the first COPY or ADD that copies
+// the entire build context ("COPY . ."), or 0 when there is none.
+func wholeContextCopyLine(ast *parser.Node) int {
+	for _, child := range ast.Children {
+		instruction := strings.ToUpper(child.Value)
+		if instruction != "COPY" && instruction != "ADD" {
+			continue
+		}
+		if hasFlag(child.Flags, "--from") {
+			continue // Copies from another stage, not the build context
+		}
+
+		var args []string
+		for node := child.Next; node != nil; node = node.Next {
+			args = append(args, node.Value)
+		}
+		if len(args) < 2 {
+			continue
+		}
+		for _, src := range args[:len(args)-1] {
+			if src == "." || src == "./" {
+				return child.StartLine
+			}
+		}
+	}
+	return 0
+}
+
+// sensitiveContextPaths returns, for each SensitivePatterns entry, the
+// shortest matching path found in the build context.
+func sensitiveContextPaths(files []string) []string {
+	var found []string
+	for _, pattern := range SensitivePatterns {
+		match := ""
+		for _, file := range files {
+			parts := strings.Split(file, "/")
+			for i, part := range parts {
+				if ok, _ := filepath.Match(pattern, part); ok {
+					candidate := strings.Join(parts[:i+1], "/")
+					if match == "" || len(candidate) < len(match) {
+						match = candidate
+					}
+					break
+				}
+			}
+		}
+		if match != "" {
+			found = append(found, match)
+		}
+	}
+	return found
+}
+
+// hasFlag reports whether an instruction carries the named flag.
+func hasFlag(flags []string, name string) bool {
+	for _, flag := range flags {
+		if flag == name || strings.HasPrefix(flag, name+"=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeTree creates empty files at the given slash-separated paths under dir
+func writeTree(t *testing.T, dir string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		path := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestContextFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{
+			name: "sensitive directories are listed but not walked",
+			want: []string{"app", "app/main.go", "node_modules", "vendor", "vendor/lib.go"},
+		},
+		{
+			name:     "excluded directories are pruned",
+			patterns: []string{"vendor", "node_modules"},
+			want:     []string{"app", "app/main.go"},
+		},
+		{
+			name:     "exclusion patterns still re-include files",
+			patterns: []string{"vendor", "!vendor/lib.go"},
+			want:     []string{"app", "app/main.go", "node_modules", "vendor/lib.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, "app/main.go", "node_modules/left-pad/index.js", "vendor/lib.go")
+
+			files, err := ContextFiles(dir, tt.patterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(files, tt.want) {
+				t.Errorf("ContextFiles = %v, want %v", files, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextDirs(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "app/cmd/main.go", "node_modules/left-pad/index.js", "vendor/lib.go")
+
+	dirs, err := ContextDirs(dir, []string{"vendor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{dir, filepath.Join(dir, "app"), filepath.Join(dir, "app", "cmd")}
+	if !slices.Equal(dirs, want) {
+		t.Errorf("ContextDirs = %v, want %v", dirs, want)
+	}
+}
+
+func TestCheckBuildContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		dockerfile   string
+		dockerignore string
+		want         []string // Snippets of the findings
+	}{
+		{
+			name:       "whole context copied",
+			dockerfile: "FROM alpine:3.20\nCOPY . /app\n",
+			want:       []string{".git", ".env", "node_modules"},
+		},
+		{
+			name:         "sensitive paths ignored",
+			dockerfile:   "FROM alpine:3.20\nCOPY . /app\n",
+			dockerignore: ".git\n.env\nnode_modules\n",
+		},
+		{
+			name:       "only selected files copied",
+			dockerfile: "FROM alpine:3.20\nCOPY app/ /app\n",
+		},
+		{
+			name:       "copy from another stage",
+			dockerfile: "FROM alpine:3.20 AS build\nFROM alpine:3.20\nCOPY --from=build . /app\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, "app/main.go", ".git/HEAD", ".env", "node_modules/left-pad/index.js")
+			dockerfilePath := filepath.Join(dir, "Dockerfile")
+			if err := os.WriteFile(dockerfilePath, []byte(tt.dockerfile), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.dockerignore != "" {
+				if err := os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte(tt.dockerignore), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ast, err := parseDockerfile([]byte(tt.dockerfile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings, err := checkBuildContext(dockerfilePath, ast)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, finding := range findings {
+				if finding.RuleID != "dockerignore-sensitive-path" {
+					t.Errorf("unexpected finding %s: %s", finding.RuleID, finding.Message)
+				}
+				got = append(got, finding.Snippet)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("findings for %s, want %s", strings.Join(got, ", "), strings.Join(tt.want, ", "))
+			}
+		})
+	}
+}
+
+func TestCheckBuildContextInvalidDockerignore(t *testing.T) {
+	dir := t.TempDir()
+	dockerfilePath := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("[\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ast, err := parseDockerfile([]byte("FROM alpine:3.20\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := checkBuildContext(dockerfilePath, ast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].RuleID != "dockerignore-syntax" {
+		t.Errorf("findings = %+v, want one dockerignore-syntax finding", findings)
+	}
+}
@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/suppression"
+)
+
+// Comment directives that suppress findings in a Dockerfile:
+//
+//	# scanrunner:ignore DL3008,DL3020 reason=pinned by the base image
+//	# scanrunner:ignore-file DL3007 reason=local development image
+//
+// ignore applies to the instruction directly below the comment, ignore-file
+// to every finding reported for the Dockerfile.
+const (
+	ignoreDirective     = "scanrunner:ignore"
+	ignoreFileDirective = "scanrunner:ignore-file"
+	reasonPrefix        = "reason="
+)
+
+// applySuppressions marks findings silenced by ignore comments. Comments
+// without a reason are not applied and are reported as findings instead.
+func applySuppressions(filePath string, ast *parser.Node, findings []model.Finding) []model.Finding {
+	var missing []model.Finding
+	for _, child := range ast.Children {
+		for _, comment := range child.PrevComment {
+			directive, rules, reason, ok := parseIgnoreComment(comment)
+			if !ok {
+				continue
+			}
+			if reason == "" {
+				finding := suppression.MissingReason(rules)
+				finding.File = filePath
+				finding.Line = child.StartLine
+				missing = append(missing, finding)
+				continue
+			}
+
+			s := suppression.Suppression{Rules: rules, Reason: reason}
+			if directive == ignoreFileDirective {
+				s.Apply(findings, func(model.Finding) bool { return true })
+				continue
+			}
+			start, end := child.StartLine, child.EndLine
+			s.Apply(findings, func(finding model.Finding) bool {
+				return (finding.File == "" || finding.File == filePath) && finding.Line >= start && finding.Line <= end
+			})
+		}
+	}
+	return append(findings, missing...)
+}
+
+// parseIgnoreComment parses "scanrunner:ignore[-file] RULE[,RULE] reason=..."
+// from a comment with the leading '#' already removed
+func parseIgnoreComment(comment string) (directive string, rules []string, reason string, ok bool) {
+	fields := strings.Fields(comment)
+	if len(fields) < 2 || (fields[0] != ignoreDirective && fields[0] != ignoreFileDirective) {
+		return "", nil, "", false
+	}
+	directive = fields[0]
+	rest := strings.TrimSpace(strings.TrimPrefix(comment, directive))
+	if i := strings.Index(rest, reasonPrefix); i >= 0 {
+		reason = strings.TrimSpace(rest[i+len(reasonPrefix):])
+		rest = rest[:i]
+	}
+	rules = suppression.ParseRules(strings.ReplaceAll(rest, " ", ","))
+	return directive, rules, reason, len(rules) > 0
+}
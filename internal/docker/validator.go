@@ -2,40 +2,113 @@ package docker
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser" // For parsing Dockerfiles
+	"github.com/mtyiska/scanrunner/internal/model"
 )
 
+// Validator validates Dockerfiles. It claims files whose name matches
+// DefaultPatterns or Patterns, or whose content starts with a FROM or ARG
+// instruction.
+type Validator struct {
+	Patterns []string // Extra file name globs treated as Dockerfiles
+}
+
+// Name returns the validator name used in results
+func (Validator) Name() string { return "dockerfile" }
+
+// Detect reports whether the file is a Dockerfile, like IsDockerfile but
+// sniffing the content already read instead of reading the file again
+func (v Validator) Detect(filePath string, content []byte) bool {
+	return MatchesName(filepath.Base(filePath), v.Patterns...) || SniffContent(content)
+}
+
+// Validate lints the Dockerfile and returns its findings
+func (Validator) Validate(ctx context.Context, filePath string, content []byte, rules model.Rules) ([]model.Finding, error) {
+	return ValidateDockerfile(ctx, filePath)
+}
+
+// Dependencies returns the paths besides the Dockerfile that its findings
+// depend on: the candidate .dockerignore files and, when the whole build
+// context is copied, the context directories the build context check lists.
+func (Validator) Dependencies(filePath string, content []byte) []string {
+	dependencies := []string{
+		filePath + ".dockerignore",
+		filepath.Join(filepath.Dir(filePath), ".dockerignore"),
+	}
+	if ast, err := parseDockerfile(content); err == nil && wholeContextCopyLine(ast) > 0 {
+		contextDir := filepath.Dir(filePath)
+		patterns, _, _ := LoadDockerignore(filePath)
+		dirs, err := ContextDirs(contextDir, patterns)
+		if err != nil {
+			dirs = []string{contextDir} // The validation reports the error; still key on the context
+		}
+		dependencies = append(dependencies, dirs...)
+	}
+	return dependencies
+}
+
 // ValidateDockerfile validates a Dockerfile for best practices, linting, and security checks.
-func ValidateDockerfile(filePath string) error {
+// The Trivy scan is stopped when ctx is cancelled.
+func ValidateDockerfile(ctx context.Context, filePath string) ([]model.Finding, error) {
 	// Step 1: Read the Dockerfile
 	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read Dockerfile: %w", err)
+		return nil, fmt.Errorf("failed to read Dockerfile: %w", err)
 	}
 
 	// Step 2: Parse and analyze the Dockerfile content
 	parsedDockerfile, err := parseDockerfile(content)
 	if err != nil {
-		return fmt.Errorf("failed to parse Dockerfile: %w", err)
+		return nil, &model.InputError{Err: err}
 	}
 
 	// Step 3: Perform linting checks
-	if err := lintDockerfile(parsedDockerfile); err != nil {
-		return fmt.Errorf("linting failed: %w", err)
+	findings := lintDockerfile(parsedDockerfile)
+
+	// Step 4: Check the build context against .dockerignore
+	contextFindings, err := checkBuildContext(filePath, parsedDockerfile)
+	if err != nil {
+		return nil, fmt.Errorf("build context check failed: %w", err)
 	}
+	findings = append(findings, contextFindings...)
 
-	// Step 4: Perform security scanning (Trivy)
-	if err := scanDockerfileForSecrets(filePath); err != nil {
-		return fmt.Errorf("security scan failed: %w", err)
+	// Step 5: Perform security scanning (Trivy)
+	if err := scanDockerfileForSecrets(ctx, filePath); err != nil {
+		return nil, fmt.Errorf("security scan failed: %w", err)
 	}
 
-	// Step 5: Return success if all checks pass
-	return nil
+	// Step 6: Attribute findings to the Dockerfile unless they already point elsewhere
+	for i := range findings {
+		if findings[i].File == "" {
+			findings[i].File = filePath
+		}
+	}
+
+	// Step 7: Apply scanrunner:ignore comments
+	return applySuppressions(filePath, parsedDockerfile, findings), nil
+}
+
+// LintDockerfile lints Dockerfile content without touching the filesystem:
+// unlike ValidateDockerfile it skips the build context check and the Trivy
+// scan. Findings are attributed to filePath and scanrunner:ignore comments apply.
+func LintDockerfile(filePath string, content []byte) ([]model.Finding, error) {
+	parsedDockerfile, err := parseDockerfile(content)
+	if err != nil {
+		return nil, err
+	}
+	findings := lintDockerfile(parsedDockerfile)
+	for i := range findings {
+		findings[i].File = filePath
+	}
+	return applySuppressions(filePath, parsedDockerfile, findings), nil
 }
 
 // parseDockerfile parses the Dockerfile content using the BuildKit parser.
@@ -49,32 +122,49 @@ func parseDockerfile(content []byte) (*parser.Node, error) {
 }
 
 // lintDockerfile performs linting and best practices validation on the parsed Dockerfile.
-func lintDockerfile(ast *parser.Node) error {
+func lintDockerfile(ast *parser.Node) []model.Finding {
+	var findings []model.Finding
 	for _, child := range ast.Children {
 		switch strings.ToUpper(child.Value) {
 		case "ADD":
-			return fmt.Errorf("line %d: use 'COPY' instead of 'ADD' for better security", child.StartLine)
+			findings = append(findings, model.Finding{
+				RuleID:   "DL3020",
+				Severity: model.SeverityMedium,
+				Line:     child.StartLine,
+				Snippet:  child.Original,
+				Message:  "use 'COPY' instead of 'ADD' for better security",
+			})
 		case "FROM":
-			if len(child.Next.Value) == 0 || strings.Contains(child.Next.Value, "latest") {
-				return fmt.Errorf("line %d: avoid using 'latest' tag in FROM directive for better reproducibility", child.StartLine)
+			if child.Next == nil || len(child.Next.Value) == 0 || strings.Contains(child.Next.Value, "latest") {
+				findings = append(findings, model.Finding{
+					RuleID:   "DL3007",
+					Severity: model.SeverityMedium,
+					Line:     child.StartLine,
+					Snippet:  child.Original,
+					Message:  "avoid using 'latest' tag in FROM directive for better reproducibility",
+				})
 			}
 		case "RUN":
 			if strings.Contains(child.Original, "apt-get install") && !strings.Contains(child.Original, "apt-get update") {
-				return fmt.Errorf("line %d: missing 'apt-get update' before 'apt-get install'", child.StartLine)
+				findings = append(findings, model.Finding{
+					RuleID:   "apt-get-update",
+					Severity: model.SeverityLow,
+					Line:     child.StartLine,
+					Snippet:  child.Original,
+					Message:  "missing 'apt-get update' before 'apt-get install'",
+				})
 			}
 		}
 	}
-	return nil
+	return findings
 }
 
-
-
 // scanDockerfileForSecrets scans the Dockerfile for secrets using Trivy.
-func scanDockerfileForSecrets(filePath string) error {
+func scanDockerfileForSecrets(ctx context.Context, filePath string) error {
 	// fmt.Printf("Scanning %s for secrets using Trivy...\n", filePath)
 
 	// Build the Trivy command to scan the file
-	cmd := exec.Command("trivy", "fs", "--security-checks", "secret", "--exit-code", "0", "--no-progress", filePath)
+	cmd := exec.CommandContext(ctx, "trivy", "fs", "--security-checks", "secret", "--exit-code", "0", "--no-progress", filePath)
 
 	// Capture the output and errors
 	var out bytes.Buffer
@@ -83,14 +173,18 @@ func scanDockerfileForSecrets(filePath string) error {
 	cmd.Stderr = &stderr
 
 	// Run the command
-    err := cmd.Run()
-    if err != nil {
-        if _, ok := err.(*exec.Error); ok {
-            return fmt.Errorf("Trivy is not installed or not in PATH. Please install it and try again")
-        }
-        fmt.Printf("Error during Trivy scan: %s\n", stderr.String())
-        return fmt.Errorf("Trivy scan failed for %s: %w", filePath, err)
-    }
+	err := cmd.Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, ok := err.(*exec.Error); ok {
+			return fmt.Errorf("Trivy is not installed or not in PATH. Please install it and try again")
+		}
+		// Log to stderr so reports written to stdout stay intact
+		log.Printf("Error during Trivy scan: %s\n", strings.TrimSpace(stderr.String()))
+		return fmt.Errorf("Trivy scan failed for %s: %w", filePath, err)
+	}
 	// Print the scan results
 	// fmt.Printf("Trivy scan results for %s:\n%s", filePath, out.String())
 	return nil
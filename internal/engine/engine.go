@@ -0,0 +1,212 @@
+// Package engine runs the scan pipeline shared by every command: discover the
+// files under a directory, render Helm charts and build Kustomize overlays,
+// validate the remaining files in parallel, then apply the ignore list, the
+// baseline and the fail threshold.
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mtyiska/scanrunner/internal/baseline"
+	"github.com/mtyiska/scanrunner/internal/cache"
+	"github.com/mtyiska/scanrunner/internal/compliance"
+	"github.com/mtyiska/scanrunner/internal/concurrency"
+	"github.com/mtyiska/scanrunner/internal/docker"
+	"github.com/mtyiska/scanrunner/internal/helm"
+	"github.com/mtyiska/scanrunner/internal/ignore"
+	"github.com/mtyiska/scanrunner/internal/kustomize"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/vcs"
+)
+
+// Options configures a scan
+type Options struct {
+	Root               string             // Directory to scan
+	Rules              model.Rules        // Rules files are validated against
+	DockerfilePatterns []string           // Extra file name globs treated as Dockerfiles
+	Helm               helm.ValuesOptions // Values used when rendering Helm charts
+	Jobs               int                // Files validated in parallel; the number of CPUs when 0
+	Cache              *cache.Cache       // Cached results of unchanged files; nil disables caching
+	Changes            *vcs.ChangeSet     // When not nil, only changed files are reported
+	ChangedLinesOnly   bool               // Also hide findings on lines Changes did not add or modify
+	Ignore             *ignore.List       // Compiled path excludes and expiring suppressions; nil for none
+	Baseline           *baseline.Baseline // Accepted findings to hide; nil for none
+	Threshold          string             // Lowest severity that fails a file; model.DefaultThreshold when empty
+	Checks             []Check            // Additional rules run on every file a validator claimed
+}
+
+// vcsDirs are version control metadata directories, never scanned
+var vcsDirs = map[string]bool{".git": true, ".hg": true, ".svn": true}
+
+// Check is an additional rule run on the content of a file after the
+// validator that claimed it. It returns its own findings for the file.
+type Check func(file model.FileResult, content []byte) []model.Finding
+
+// Result is the outcome of a scan
+type Result struct {
+	Files      []model.FileResult // One entry per validated file, chart or overlay
+	Expired    []ignore.Entry     // Ignore entries that have expired and were not applied
+	Baselined  int                // Findings hidden by the baseline
+	StartedAt  time.Time          // When validation started
+	FinishedAt time.Time          // When validation finished
+}
+
+// Failed reports whether any validated file has findings at or above the
+// threshold. Files that could not be validated are reported by Errored.
+func (r *Result) Failed() bool {
+	for _, file := range r.Files {
+		if file.Status == model.StatusFail && !file.Errored() {
+			return true
+		}
+	}
+	return false
+}
+
+// Errored reports whether any file could not be validated, for example
+// because it cannot be parsed, Trivy is missing or a chart fails to render
+func (r *Result) Errored() bool {
+	for _, file := range r.Files {
+		if file.Errored() {
+			return true
+		}
+	}
+	return false
+}
+
+// Run scans opts.Root and validates every file found. When ctx is cancelled,
+// validation stops and ctx.Err() is returned.
+func Run(ctx context.Context, opts Options) (*Result, error) {
+	if opts.ChangedLinesOnly && opts.Changes == nil {
+		return nil, errors.New("changed lines can only be reported for a change set")
+	}
+	if opts.Jobs <= 0 {
+		opts.Jobs = concurrency.DefaultJobs()
+	}
+	if opts.Threshold == "" {
+		opts.Threshold = model.DefaultThreshold
+	}
+
+	files, err := Discover(opts.Root, opts.Ignore, opts.DockerfilePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{StartedAt: time.Now()}
+	if len(files) > 0 {
+		// Charts and overlays load every file for context, but only changed
+		// files are reported
+		files, results, err := compliance.ValidateHelmCharts(ctx, files, opts.Helm, opts.Rules, opts.Changes, opts.Jobs)
+		if err != nil {
+			return nil, err
+		}
+		files, overlayResults, err := compliance.ValidateKustomizations(ctx, files, opts.Rules, opts.Changes, opts.Jobs)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, overlayResults...)
+
+		fileResults, err := compliance.ValidateFiles(ctx, opts.Changes.Filter(files), opts.Rules, opts.Jobs, opts.Cache, compliance.NewRegistry(opts.DockerfilePatterns))
+		if err != nil {
+			return nil, err
+		}
+		for i := range fileResults {
+			runChecks(&fileResults[i], opts.Checks)
+		}
+		dropCoveredFindings(fileResults)
+		result.Files = append(results, fileResults...)
+	}
+
+	result.Expired = opts.Ignore.Apply(result.Files, opts.Root, time.Now())
+	if opts.ChangedLinesOnly {
+		result.Files = compliance.FilterChangedLines(result.Files, opts.Changes)
+	}
+	result.Files, result.Baselined = opts.Baseline.Filter(result.Files, opts.Root)
+	model.ApplyThreshold(result.Files, opts.Threshold)
+	result.FinishedAt = time.Now()
+	return result, nil
+}
+
+// dropCoveredFindings removes findings a file reports about another file that
+// was validated without error in the same scan, such as the Dockerfile a
+// Compose build section references, so they are not counted twice. Findings
+// about files outside the scan, or whose own validation failed, are kept.
+func dropCoveredFindings(results []model.FileResult) {
+	covered := make(map[string]bool)
+	for _, result := range results {
+		if result.Validator != "" && result.Error == "" {
+			covered[filepath.Clean(result.File)] = true
+		}
+	}
+	for i, result := range results {
+		var findings []model.Finding
+		for _, finding := range result.Findings {
+			other := finding.File != "" && filepath.Clean(finding.File) != filepath.Clean(result.File)
+			if other && covered[filepath.Clean(finding.File)] {
+				continue
+			}
+			findings = append(findings, finding)
+		}
+		results[i].Findings = findings
+	}
+}
+
+// runChecks appends the findings of checks to a file validated without error.
+// Charts and overlays are not checked since their rendered output is not on disk.
+func runChecks(result *model.FileResult, checks []Check) {
+	if len(checks) == 0 || result.Validator == "" || result.Error != "" {
+		return
+	}
+	content, err := os.ReadFile(result.File)
+	if err != nil {
+		return
+	}
+	for _, check := range checks {
+		result.Findings = append(result.Findings, check(*result, content)...)
+	}
+}
+
+// Discover returns the YAML, JSON and Terraform files, kustomization files and
+// Dockerfiles under root, skipping version control directories and paths
+// excluded by ignores
+func Discover(root string, ignores *ignore.List, dockerfilePatterns []string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip excluded paths
+		if rel, err := filepath.Rel(root, filePath); err == nil && rel != "." && ignores.Excluded(rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Skip directories, and version control metadata entirely
+		if info.IsDir() {
+			if filePath != root && vcsDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Check for YAML, JSON, Terraform, kustomization files (including the
+		// extensionless Kustomization), or Dockerfiles (by name or content)
+		ext := strings.ToLower(filepath.Ext(filePath))
+		if ext == ".yaml" || ext == ".yml" || ext == ".json" || ext == ".tf" || kustomize.IsKustomizationFile(filePath) || docker.IsDockerfile(filePath, dockerfilePatterns...) {
+			files = append(files, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	return files, nil
+}
@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+func TestRunValidatesDockerfilePatterns(t *testing.T) {
+	root := t.TempDir()
+	// The template header hides the FROM line from content sniffing, so only
+	// the configured pattern identifies the file as a Dockerfile
+	path := filepath.Join(root, "app.dockerfile.tmpl")
+	content := "{{- /* rendered by the release tooling */ -}}\nFROM alpine:latest\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Run(context.Background(), Options{
+		Root:               root,
+		DockerfilePatterns: []string{"*.dockerfile.tmpl"},
+		Jobs:               1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Files) != 1 {
+		t.Fatalf("got %d results, want 1: %+v", len(result.Files), result.Files)
+	}
+	file := result.Files[0]
+	if file.Validator != "dockerfile" || file.Status == model.StatusSkipped {
+		t.Errorf("got validator %q status %s, want the file validated as a Dockerfile", file.Validator, file.Status)
+	}
+}
+
+func TestDiscoverSkipsVCSDirectories(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"deploy.yaml", ".git/config.yaml", ".hg/store/x.yaml", ".svn/entries.json"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("kind: ConfigMap\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := Discover(root, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(root, "deploy.yaml")}; !slices.Equal(files, want) {
+		t.Errorf("Discover = %v, want %v", files, want)
+	}
+}
+
+func TestRunCountsBuildDockerfileFindingsOnce(t *testing.T) {
+	// A stub Trivy lets the Dockerfile's own validation succeed
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "trivy"), []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	root := t.TempDir()
+	files := map[string]string{
+		"Dockerfile":   "FROM alpine:latest\n",
+		"compose.yaml": "services:\n  web:\n    build: .\n    privileged: true\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := Run(context.Background(), Options{Root: root, Jobs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, file := range result.Files {
+		if file.Error != "" {
+			t.Fatalf("%s: %s", file.File, file.Error)
+		}
+		for _, finding := range file.Findings {
+			if finding.RuleID == "DL3007" {
+				count++
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("DL3007 reported %d times, want once: %+v", count, result.Files)
+	}
+}
+
+func TestRunSeparatesErroredFiles(t *testing.T) {
+	// Without Trivy the Dockerfile cannot be validated
+	t.Setenv("PATH", t.TempDir())
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		invalid bool
+	}{
+		{name: "unparsable YAML", file: "deploy.yaml", content: "kind: [Pod\n", invalid: true},
+		{name: "unparsable HCL", file: "main.tf", content: "resource \"aws_s3_bucket\" {\n", invalid: true},
+		{name: "missing Trivy", file: "Dockerfile", content: "FROM alpine:3.20\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, tt.file), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Run(context.Background(), Options{Root: root, Jobs: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Errored() || result.Failed() {
+				t.Fatalf("Errored() = %t, Failed() = %t, want only Errored: %+v", result.Errored(), result.Failed(), result.Files)
+			}
+			if got := result.Files[0].InvalidInput; got != tt.invalid {
+				t.Errorf("InvalidInput = %t, want %t (%s)", got, tt.invalid, result.Files[0].Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// locate.go
+package fileparser
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// LocateLine returns the 1-based line of a dot-separated field path in the
+// first YAML document of content. Path segments may carry "[]" (first item) or
+// "[N]" (item N) array notation, as in rule field paths. When the path does
+// not exist, the line of the deepest existing ancestor is returned, so
+// findings about missing fields point at the object that should hold them.
+// It returns 0 when content cannot be parsed.
+func LocateLine(content []byte, path string) int {
+	return LocateDocumentLine(content, 0, path)
+}
+
+// LocateDocumentLine is LocateLine for the document at index in a
+// multi-document stream. Empty documents are not counted, matching the
+// indexes of ParseYAMLDocuments. It returns 0 when the document does not exist.
+func LocateDocumentLine(content []byte, index int, path string) int {
+	root := documentNode(content, index)
+	if root == nil {
+		return 0
+	}
+
+	current := root
+	line := current.Line
+	if path == "" {
+		return line
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		key, item := part, -1
+		if open := strings.Index(part, "["); open >= 0 && strings.HasSuffix(part, "]") {
+			key = part[:open]
+			item = 0
+			if n, err := strconv.Atoi(part[open+1 : len(part)-1]); err == nil {
+				item = n
+			}
+		}
+
+		keyNode, value := mappingValue(current, key)
+		if value == nil {
+			return line
+		}
+		current, line = value, keyNode.Line
+
+		if item >= 0 {
+			if current.Kind != yamlv3.SequenceNode || item >= len(current.Content) {
+				return line
+			}
+			current = current.Content[item]
+			line = current.Line
+		}
+	}
+	return line
+}
+
+// documentNode returns the root node of the index-th non-empty document in
+// content, or nil when there is no such document or content cannot be parsed
+func documentNode(content []byte, index int) *yamlv3.Node {
+	decoder := yamlv3.NewDecoder(bytes.NewReader(content))
+	for {
+		var document yamlv3.Node
+		if err := decoder.Decode(&document); err != nil {
+			return nil
+		}
+		if len(document.Content) == 0 || len(document.Content[0].Content) == 0 {
+			continue
+		}
+		if index == 0 {
+			return document.Content[0]
+		}
+		index--
+	}
+}
+
+// mappingValue returns the key and value nodes for key in a mapping node
+func mappingValue(node *yamlv3.Node, key string) (*yamlv3.Node, *yamlv3.Node) {
+	if node.Kind != yamlv3.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
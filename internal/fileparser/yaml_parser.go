@@ -2,7 +2,9 @@
 package fileparser
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -30,16 +32,19 @@ func ConvertMapKeys(data interface{}) interface{} {
 
 // ParseAndConvertYAML loads a YAML file and converts it to map[string]interface{}
 func ParseAndConvertYAML(filePath string) (map[string]interface{}, error) {
-	file, err := os.Open(filePath)
+	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open YAML file: %w", err)
 	}
-	defer file.Close()
+	return ParseYAMLContent(content)
+}
 
+// ParseYAMLContent decodes the first YAML document in content and converts it to map[string]interface{}
+func ParseYAMLContent(content []byte) (map[string]interface{}, error) {
 	var parsedData map[interface{}]interface{}
-	decoder := yaml.NewDecoder(file)
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
 	decoder.SetStrict(false) // Allow extra fields
-	if err := decoder.Decode(&parsedData); err != nil {
+	if err := decoder.Decode(&parsedData); err != nil && err != io.EOF {
 		return nil, fmt.Errorf("failed to decode YAML: %w", err)
 	}
 
@@ -48,6 +53,29 @@ func ParseAndConvertYAML(filePath string) (map[string]interface{}, error) {
 	return convertedData, nil
 }
 
+// ParseYAMLDocuments decodes every document in a multi-document YAML stream,
+// skipping empty documents
+func ParseYAMLDocuments(content []byte) ([]map[string]interface{}, error) {
+	var documents []map[string]interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.SetStrict(false) // Allow extra fields
+	for {
+		var parsedData map[interface{}]interface{}
+		err := decoder.Decode(&parsedData)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode YAML: %w", err)
+		}
+		if len(parsedData) == 0 {
+			continue
+		}
+		documents = append(documents, ConvertMapKeys(parsedData).(map[string]interface{}))
+	}
+	return documents, nil
+}
+
 // GetField retrieves a nested field from a parsed YAML structure based on a dot-separated path.
 func GetField(data map[string]interface{}, path string) (interface{}, error) {
 	parts := strings.Split(path, ".")
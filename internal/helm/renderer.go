@@ -0,0 +1,112 @@
+package helm
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// ChartFile is the file that marks a directory as a Helm chart
+const ChartFile = "Chart.yaml"
+
+// ValuesOptions holds the values overrides applied when rendering a chart,
+// mirroring `helm template --values` and `--set`
+type ValuesOptions struct {
+	ValueFiles []string // Values files merged in order, later files win
+	Values     []string // key=value overrides applied after the values files
+}
+
+// FindCharts returns the root directories of the Helm charts among the scanned
+// files. Subcharts vendored under a chart's charts/ directory are rendered with
+// their parent and are not returned separately.
+func FindCharts(files []string) []string {
+	var charts []string
+	for _, file := range files {
+		if filepath.Base(file) == ChartFile {
+			charts = append(charts, filepath.Dir(file))
+		}
+	}
+	sort.Strings(charts)
+
+	var roots []string
+	for _, chart := range charts {
+		nested := false
+		for _, root := range roots {
+			if InChart(chart, root) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			roots = append(roots, chart)
+		}
+	}
+	return roots
+}
+
+// InChart reports whether path lies inside chartDir
+func InChart(path, chartDir string) bool {
+	rel, err := filepath.Rel(chartDir, path)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+// RenderChart renders a chart in-process, without cluster access, and returns
+// the rendered manifests keyed by the path of the template that produced them.
+// Partials, NOTES.txt and templates that render to nothing are omitted.
+func RenderChart(chartDir string, opts ValuesOptions) (map[string]string, error) {
+	chart, err := loader.Load(chartDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+
+	valueOpts := values.Options{ValueFiles: opts.ValueFiles, Values: opts.Values}
+	overrides, err := valueOpts.MergeValues(getter.Providers{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge values: %w", err)
+	}
+	if err := chartutil.ProcessDependenciesWithMerge(chart, overrides); err != nil {
+		return nil, fmt.Errorf("failed to process chart dependencies: %w", err)
+	}
+
+	releaseOpts := chartutil.ReleaseOptions{
+		Name:      "release-name",
+		Namespace: "default",
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(chart, overrides, releaseOpts, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare values: %w", err)
+	}
+
+	rendered, err := engine.Render(chart, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	// Rendered names are "<chart>/templates/..." (or "<chart>/charts/<sub>/templates/...");
+	// dropping the leading chart name yields the path relative to chartDir
+	manifests := make(map[string]string)
+	for name, content := range rendered {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		parts := strings.SplitN(filepath.ToSlash(name), "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		manifests[filepath.Join(chartDir, filepath.FromSlash(parts[1]))] = content
+	}
+	return manifests, nil
+}
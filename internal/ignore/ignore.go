@@ -0,0 +1,204 @@
+package ignore
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/moby/patternmatcher"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"gopkg.in/yaml.v2"
+)
+
+// FileName is the ignore file read from the root of the scanned directory
+const FileName = ".scanrunnerignore"
+
+// dateLayout is the format of expiry dates
+const dateLayout = "2006-01-02"
+
+// List holds path excludes and time-limited finding suppressions. It is read
+// from FileName and from the "ignore" section of the configuration file.
+type List struct {
+	Exclude  []string `yaml:"exclude"`  // Path globs never scanned, e.g. "vendor/**"
+	Suppress []Entry  `yaml:"suppress"` // Findings to suppress until an expiry date
+
+	exclude *patternmatcher.PatternMatcher
+}
+
+// Entry suppresses the findings matching all of its selectors. An empty
+// selector matches everything, but at least one selector must be set.
+type Entry struct {
+	Paths     []string `yaml:"paths"`     // File globs relative to the scan root
+	Rules     []string `yaml:"rules"`     // Rule IDs
+	Resources []string `yaml:"resources"` // Resource globs, e.g. "Deployment/legacy-*"
+	Owner     string   `yaml:"owner"`     // Who is accountable for the exception
+	Reason    string   `yaml:"reason"`    // Why the exception is acceptable
+	Expires   string   `yaml:"expires"`   // Last day the entry applies, as YYYY-MM-DD
+
+	expires time.Time
+	paths   *patternmatcher.PatternMatcher
+}
+
+// Load reads an ignore file. A missing file yields an empty list and no error.
+func Load(filePath string) (*List, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
+	var list List
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse ignore file %s: %w", filePath, err)
+	}
+	if err := list.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid ignore file %s: %w", filePath, err)
+	}
+	return &list, nil
+}
+
+// Compile checks every entry and prepares its matchers. Each entry needs an
+// owner and a valid expiry date so that exceptions cannot live forever.
+func (l *List) Compile() error {
+	var err error
+	if l.exclude, err = patternmatcher.New(l.Exclude); err != nil {
+		return fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	for i := range l.Suppress {
+		entry := &l.Suppress[i]
+		if len(entry.Paths) == 0 && len(entry.Rules) == 0 && len(entry.Resources) == 0 {
+			return fmt.Errorf("suppress entry %d has no paths, rules or resources and would suppress every finding", i+1)
+		}
+		if entry.Owner == "" {
+			return fmt.Errorf("suppress entry %d has no owner", i+1)
+		}
+		expires, err := time.Parse(dateLayout, entry.Expires)
+		if err != nil {
+			return fmt.Errorf("suppress entry %d has an invalid expires date %q (want YYYY-MM-DD)", i+1, entry.Expires)
+		}
+		entry.expires = expires
+		if entry.paths, err = patternmatcher.New(entry.Paths); err != nil {
+			return fmt.Errorf("suppress entry %d has an invalid path pattern: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Merge appends the excludes and entries of other to l. Compile must be
+// called again before the merged list is used.
+func (l *List) Merge(other *List) {
+	if other == nil {
+		return
+	}
+	l.Exclude = append(l.Exclude, other.Exclude...)
+	l.Suppress = append(l.Suppress, other.Suppress...)
+}
+
+// Excluded reports whether a path relative to the scan root matches an exclude pattern
+func (l *List) Excluded(relPath string) bool {
+	if l == nil || l.exclude == nil || len(l.Exclude) == 0 {
+		return false
+	}
+	excluded, _ := l.exclude.MatchesOrParentMatches(filepath.ToSlash(relPath))
+	return excluded
+}
+
+// Expired reports whether the entry no longer applies at now
+func (e Entry) Expired(now time.Time) bool {
+	return now.After(e.expires.AddDate(0, 0, 1))
+}
+
+// matches reports whether the entry selects the finding
+func (e Entry) matches(finding model.Finding, relPath string) bool {
+	if len(e.Rules) > 0 && !contains(e.Rules, finding.RuleID) {
+		return false
+	}
+	if len(e.Paths) > 0 {
+		if matched, _ := e.paths.MatchesOrParentMatches(relPath); !matched {
+			return false
+		}
+	}
+	if len(e.Resources) > 0 && !matchesAny(e.Resources, finding.Resource) {
+		return false
+	}
+	return true
+}
+
+// justification describes the entry on the findings it suppresses
+func (e Entry) justification() string {
+	reason := e.Reason
+	if reason == "" {
+		reason = "listed in " + FileName
+	}
+	return fmt.Sprintf("%s (owner: %s, expires %s)", reason, e.Owner, e.Expires)
+}
+
+// Apply marks findings selected by unexpired entries as suppressed and updates
+// each file's status accordingly. File paths are matched relative to root.
+// It returns the entries that have expired and were therefore not applied.
+func (l *List) Apply(results []model.FileResult, root string, now time.Time) []Entry {
+	if l == nil {
+		return nil
+	}
+	var active, expired []Entry
+	for _, entry := range l.Suppress {
+		if entry.Expired(now) {
+			expired = append(expired, entry)
+		} else {
+			active = append(active, entry)
+		}
+	}
+	if len(active) == 0 {
+		return expired
+	}
+
+	for i := range results {
+		result := &results[i]
+		for j := range result.Findings {
+			finding := &result.Findings[j]
+			if finding.Suppressed {
+				continue
+			}
+			file := finding.File
+			if file == "" {
+				file = result.File
+			}
+			relPath := model.RelativePath(file, root)
+			for _, entry := range active {
+				if entry.matches(*finding, relPath) {
+					finding.Suppressed = true
+					finding.Justification = entry.justification()
+					break
+				}
+			}
+		}
+		if result.Status == model.StatusFail && result.Error == "" && !model.Exceeds(result.Findings, model.DefaultThreshold) {
+			result.Status = model.StatusPass
+		}
+	}
+	return expired
+}
+
+// matchesAny reports whether value matches one of the globs
+func matchesAny(globs []string, value string) bool {
+	for _, glob := range globs {
+		if matched, _ := path.Match(glob, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether values includes value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
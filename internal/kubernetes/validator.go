@@ -1,33 +1,119 @@
 package kubernetes
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/mtyiska/scanrunner/internal/fileparser"
 	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/internal/suppression"
 )
 
+// Annotations that suppress findings for a resource
+const (
+	IgnoreAnnotation       = "scanrunner.io/ignore"        // Comma-separated rule IDs to suppress
+	IgnoreReasonAnnotation = "scanrunner.io/ignore-reason" // Required justification for the suppression
+)
+
+// Validator validates Kubernetes manifests. It claims YAML and JSON files
+// in which any document declares both apiVersion and kind.
+type Validator struct{}
+
+// Name returns the validator name used in results
+func (Validator) Name() string { return "kubernetes" }
+
+// Detect reports whether the file content looks like a Kubernetes manifest
+func (Validator) Detect(filePath string, content []byte) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return false
+	}
+	documents, err := fileparser.ParseYAMLDocuments(content)
+	if err != nil {
+		return false
+	}
+	for _, document := range documents {
+		_, hasAPIVersion := document["apiVersion"]
+		_, hasKind := document["kind"]
+		if hasAPIVersion && hasKind {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate parses every document of the manifest and returns their findings
+func (Validator) Validate(ctx context.Context, filePath string, content []byte, rules model.Rules) ([]model.Finding, error) {
+	documents, err := fileparser.ParseYAMLDocuments(content)
+	if err != nil {
+		return nil, &model.InputError{Err: fmt.Errorf("error parsing YAML file: %w", err)}
+	}
+	var findings []model.Finding
+	for index, document := range documents {
+		for _, finding := range ValidateKubernetesManifest(document, rules) {
+			finding.File = filePath
+			finding.Line = fileparser.LocateDocumentLine(content, index, finding.Path)
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
 // ValidateKubernetesManifest validates a parsed Kubernetes manifest for compliance and best practices.
-func ValidateKubernetesManifest(parsedData map[string]interface{}, rules model.Rules) error {
+func ValidateKubernetesManifest(parsedData map[string]interface{}, rules model.Rules) []model.Finding {
+	var findings []model.Finding
+
 	// Step 1: Validate Required Fields (from rules)
 	for _, field := range rules.RequiredFields {
 		if err := fileparser.ValidateField(parsedData, field); err != nil {
-			return fmt.Errorf("missing or invalid required field: %s, error: %w", field, err)
+			findings = append(findings, model.Finding{
+				RuleID:   "required-field",
+				Severity: model.SeverityMedium,
+				Path:     field,
+				Message:  fmt.Sprintf("missing or invalid required field: %s, error: %v", field, err),
+			})
 		}
 	}
 
 	// Step 3: PodSecurity Checks
-	if err := validatePodSecurity(parsedData); err != nil {
-		return fmt.Errorf("PodSecurity validation failed: %w", err)
-	}
+	findings = append(findings, validatePodSecurity(parsedData)...)
 
 	// Step 4: Network Policy Validation
-	if err := validateNetworkPolicies(parsedData); err != nil {
-		return fmt.Errorf("NetworkPolicy validation failed: %w", err)
+	findings = append(findings, validateNetworkPolicies(parsedData)...)
+
+	// Step 5: Apply suppression annotations
+	findings = applySuppressions(parsedData, findings)
+
+	resource := ResourceName(parsedData)
+	for i := range findings {
+		findings[i].Resource = resource
 	}
+	return findings
+}
 
-	return nil
+// applySuppressions marks findings silenced by the resource's ignore
+// annotation. Without a justification annotation nothing is suppressed and
+// a finding is reported instead.
+func applySuppressions(data map[string]interface{}, findings []model.Finding) []model.Finding {
+	annotations, _ := getField(data, "metadata.annotations")
+	annotationMap, _ := annotations.(map[string]interface{})
+	ignore, _ := annotationMap[IgnoreAnnotation].(string)
+	rules := suppression.ParseRules(ignore)
+	if len(rules) == 0 {
+		return findings
+	}
+
+	reason, _ := annotationMap[IgnoreReasonAnnotation].(string)
+	if strings.TrimSpace(reason) == "" {
+		missing := suppression.MissingReason(rules)
+		missing.Path = "metadata.annotations"
+		return append(findings, missing)
+	}
+
+	suppression.Suppression{Rules: rules, Reason: strings.TrimSpace(reason)}.Apply(findings, func(model.Finding) bool { return true })
+	return findings
 }
 
 // Helper function to retrieve a nested field from the manifest
@@ -59,46 +145,83 @@ func getField(data map[string]interface{}, path string) (interface{}, bool) {
 }
 
 // Helper function for PodSecurity validation
-func validatePodSecurity(data map[string]interface{}) error {
+func validatePodSecurity(data map[string]interface{}) []model.Finding {
+	var findings []model.Finding
 	containersPath := "spec.containers"
 	if containers, exists := getField(data, containersPath); exists {
 		containerList, ok := containers.([]interface{})
 		if !ok {
-			return fmt.Errorf("containers field is not an array")
+			return []model.Finding{{
+				RuleID:   "pss-security-context",
+				Severity: model.SeverityMedium,
+				Path:     containersPath,
+				Message:  "containers field is not an array",
+			}}
 		}
-		for _, container := range containerList {
+		for i, container := range containerList {
+			containerPath := fmt.Sprintf("%s[%d]", containersPath, i)
 			containerMap, ok := container.(map[string]interface{})
 			if !ok {
 				continue
 			}
 			if securityContext, exists := containerMap["securityContext"].(map[string]interface{}); exists {
 				if runAsRoot, ok := securityContext["runAsNonRoot"].(bool); !ok || !runAsRoot {
-					return fmt.Errorf("container must set securityContext.runAsNonRoot to true")
+					findings = append(findings, model.Finding{
+						RuleID:   "pss-run-as-non-root",
+						Severity: model.SeverityHigh,
+						Path:     containerPath + ".securityContext.runAsNonRoot",
+						Message:  "container must set securityContext.runAsNonRoot to true",
+					})
 				}
 			} else {
-				return fmt.Errorf("missing securityContext in container spec")
+				findings = append(findings, model.Finding{
+					RuleID:   "pss-security-context",
+					Severity: model.SeverityHigh,
+					Path:     containerPath + ".securityContext",
+					Message:  "missing securityContext in container spec",
+				})
 			}
 		}
 	}
-	return nil
+	return findings
 }
 
 // Helper function for Network Policy validation
-func validateNetworkPolicies(data map[string]interface{}) error {
+func validateNetworkPolicies(data map[string]interface{}) []model.Finding {
 	// Check if the resource kind is a workload that might need a NetworkPolicy
 	if kind, exists := data["kind"]; exists {
 		kindStr, ok := kind.(string)
 		if !ok {
-			return fmt.Errorf("invalid kind field format")
+			return []model.Finding{{
+				RuleID:   "network-policy",
+				Severity: model.SeverityMedium,
+				Path:     "kind",
+				Message:  "invalid kind field format",
+			}}
 		}
 
 		// NetworkPolicy is not required for non-NetworkPolicy kinds
 		if kindStr != "NetworkPolicy" {
-			fmt.Println("Warning: No NetworkPolicy defined for the workload. Consider adding one for better security.")
-			return nil
+			return []model.Finding{{
+				RuleID:   "network-policy",
+				Severity: model.SeverityLow,
+				Path:     "kind",
+				Message:  "No NetworkPolicy defined for the workload. Consider adding one for better security.",
+			}}
 		}
 	}
 
 	// If kind is NetworkPolicy, validation passes
 	return nil
 }
+
+// ResourceName returns "Kind/name" for a manifest, used to tell resources
+// apart when one file holds several documents
+func ResourceName(data map[string]interface{}) string {
+	kind, _ := data["kind"].(string)
+	name := ""
+	if metadata, ok := data["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+	}
+	return fmt.Sprintf("%s/%s", kind, name)
+}
@@ -0,0 +1,57 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+func TestValidateEveryDocument(t *testing.T) {
+	content := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: nginx:1.27
+`)
+	var validator Validator
+	if !validator.Detect("deploy.yaml", content) {
+		t.Fatal("Detect = false, want the manifest claimed")
+	}
+
+	findings, err := validator.Validate(context.Background(), "deploy.yaml", content, model.Rules{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		resource string
+		line     int
+	}{
+		{"ConfigMap/settings", 2},
+		{"Pod/web", 7},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("got %d findings, want %d: %+v", len(findings), len(want), findings)
+	}
+	for i, w := range want {
+		got := findings[i]
+		if got.RuleID != "network-policy" || got.Resource != w.resource || got.Line != w.line {
+			t.Errorf("finding %d: got %s on %s at line %d, want network-policy on %s at line %d", i, got.RuleID, got.Resource, got.Line, w.resource, w.line)
+		}
+	}
+}
+
+func TestDetectLaterDocument(t *testing.T) {
+	content := []byte("# generated\n---\nnote: not a resource\n---\napiVersion: v1\nkind: Service\n")
+	if !(Validator{}).Detect("service.yaml", content) {
+		t.Error("Detect = false, want a manifest whose later document is a resource claimed")
+	}
+}
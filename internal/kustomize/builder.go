@@ -0,0 +1,144 @@
+package kustomize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/fileparser"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// KustomizationFileNames lists the file names kustomize recognises, in lookup order
+var KustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// Kustomization describes one kustomization directory found in the scan
+type Kustomization struct {
+	Dir        string   // Directory holding the kustomization file
+	File       string   // Path of the kustomization file itself
+	References []string // Local directories pulled in through resources, bases and components
+	Patches    []string // Local patch files, which are fragments rather than full manifests
+}
+
+// IsKustomizationFile reports whether path is a kustomization file
+func IsKustomizationFile(path string) bool {
+	base := filepath.Base(path)
+	for _, name := range KustomizationFileNames {
+		if base == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadKustomization reads a kustomization file and resolves its local
+// references and patch files relative to its directory
+func LoadKustomization(path string) (Kustomization, error) {
+	k := Kustomization{Dir: filepath.Dir(path), File: path}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return k, fmt.Errorf("failed to read kustomization: %w", err)
+	}
+	data, err := fileparser.ParseYAMLContent(content)
+	if err != nil {
+		return k, &model.InputError{Err: fmt.Errorf("failed to parse kustomization: %w", err)}
+	}
+
+	for _, key := range []string{"resources", "bases", "components"} {
+		for _, ref := range stringList(data[key]) {
+			refPath := filepath.Join(k.Dir, ref)
+			if info, err := os.Stat(refPath); err == nil && info.IsDir() {
+				k.References = append(k.References, refPath)
+			}
+		}
+	}
+
+	// patchesStrategicMerge entries are either file paths or inline YAML
+	for _, patch := range stringList(data["patchesStrategicMerge"]) {
+		if !strings.Contains(patch, "\n") {
+			k.Patches = append(k.Patches, filepath.Join(k.Dir, patch))
+		}
+	}
+	for _, key := range []string{"patches", "patchesJson6902"} {
+		if list, ok := data[key].([]interface{}); ok {
+			for _, item := range list {
+				if patch, ok := item.(map[string]interface{}); ok {
+					if path, ok := patch["path"].(string); ok {
+						k.Patches = append(k.Patches, filepath.Join(k.Dir, path))
+					}
+				}
+			}
+		}
+	}
+	return k, nil
+}
+
+// FindOverlays returns the kustomizations that no other kustomization in the
+// scan references. These are the overlays that actually ship; bases are built
+// as part of them.
+func FindOverlays(kustomizations []Kustomization) []Kustomization {
+	referenced := make(map[string]bool)
+	for _, k := range kustomizations {
+		for _, ref := range k.References {
+			referenced[filepath.Clean(ref)] = true
+		}
+	}
+
+	var overlays []Kustomization
+	for _, k := range kustomizations {
+		if !referenced[filepath.Clean(k.Dir)] {
+			overlays = append(overlays, k)
+		}
+	}
+	sort.Slice(overlays, func(i, j int) bool { return overlays[i].Dir < overlays[j].Dir })
+	return overlays
+}
+
+// Resource is one resource produced by building an overlay
+type Resource struct {
+	ID   string                 // Kind/name of the resource
+	Data map[string]interface{} // Resource content
+}
+
+// Build runs kustomize in-process against an overlay directory and returns the
+// resulting resources
+func Build(dir string) ([]Resource, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := kustomizer.Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization: %w", err)
+	}
+
+	var resources []Resource
+	for _, res := range resMap.Resources() {
+		data, err := res.Map()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource %s: %w", res.CurId(), err)
+		}
+		resources = append(resources, Resource{
+			ID:   fmt.Sprintf("%s/%s", res.GetKind(), res.GetName()),
+			Data: data,
+		})
+	}
+	return resources, nil
+}
+
+// stringList returns the string items of a YAML list
+func stringList(value interface{}) []string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var items []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			items = append(items, s)
+		}
+	}
+	return items
+}
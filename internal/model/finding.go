@@ -0,0 +1,120 @@
+// model/finding.go
+package model
+
+import "errors"
+
+// Status values reported for each validated file
+const (
+	StatusPass    = "PASS"
+	StatusFail    = "FAIL"
+	StatusSkipped = "SKIPPED"
+)
+
+// Severity values attached to findings, from least to most severe
+const (
+	SeverityInfo     = "info"     // Informational; never fails a file unless the threshold is info
+	SeverityLow      = "low"      // Best-practice gaps
+	SeverityMedium   = "medium"   // Misconfigurations with limited impact
+	SeverityHigh     = "high"     // Misconfigurations that weaken isolation or leak secrets
+	SeverityCritical = "critical" // Misconfigurations that directly expose the host, cloud account or CI
+)
+
+// Severities lists the severities from least to most severe
+var Severities = []string{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// DefaultThreshold is the lowest severity that fails a file unless another
+// threshold is configured
+const DefaultThreshold = SeverityMedium
+
+// Finding represents a single rule violation detected by a validator
+type Finding struct {
+	RuleID   string `json:"rule_id"`            // Stable identifier of the rule that produced the finding
+	Severity string `json:"severity"`           // One of Severities
+	File     string `json:"file"`               // File the finding applies to
+	Line     int    `json:"line,omitempty"`     // 1-based line number, 0 when unknown
+	Resource string `json:"resource,omitempty"` // Resource within the file, e.g. "Deployment/web" or "service/db"
+	Path     string `json:"path,omitempty"`     // Field path within the file, when the finding concerns a field
+	Snippet  string `json:"snippet,omitempty"`  // Offending text, e.g. an instruction or action reference, telling apart findings of one rule on the same resource and path
+	Message  string `json:"message"`            // Human-readable description
+
+	Suppressed    bool   `json:"suppressed,omitempty"`    // Silenced by an annotation or comment; kept for audit
+	Justification string `json:"justification,omitempty"` // Reason given for the suppression
+}
+
+// FileResult holds the outcome of validating one file
+type FileResult struct {
+	File      string    `json:"file"`                // Path of the validated file
+	Validator string    `json:"validator,omitempty"` // Name of the validator that claimed the file
+	Status    string    `json:"status"`              // StatusPass, StatusFail or StatusSkipped
+	Findings  []Finding `json:"findings,omitempty"`  // Findings reported by the validator
+	Error     string    `json:"error,omitempty"`     // Reason the file could not be validated or was skipped
+
+	InvalidInput bool `json:"-"` // Error is caused by content that cannot be parsed rather than by a failing validator
+}
+
+// Errored reports whether the file could not be validated. Skipped files are
+// not errors.
+func (r FileResult) Errored() bool {
+	return r.Error != "" && r.Status != StatusSkipped
+}
+
+// InputError is a validation error caused by file content that cannot be
+// parsed, rather than by a failure of the validator or the tools it runs
+type InputError struct {
+	Err error
+}
+
+func (e *InputError) Error() string { return e.Err.Error() }
+func (e *InputError) Unwrap() error { return e.Err }
+
+// IsInputError reports whether err is or wraps an InputError
+func IsInputError(err error) bool {
+	var inputErr *InputError
+	return errors.As(err, &inputErr)
+}
+
+// SeverityRank orders severities from 0 (info) to 4 (critical). Unknown
+// severities rank below info.
+func SeverityRank(severity string) int {
+	for rank, s := range Severities {
+		if s == severity {
+			return rank
+		}
+	}
+	return -1
+}
+
+// ValidSeverity reports whether severity is one of Severities
+func ValidSeverity(severity string) bool {
+	return SeverityRank(severity) >= 0
+}
+
+// AtLeast reports whether severity is threshold or more severe
+func AtLeast(severity, threshold string) bool {
+	return SeverityRank(severity) >= SeverityRank(threshold)
+}
+
+// Exceeds reports whether any finding that is not suppressed is at or above the threshold
+func Exceeds(findings []Finding, threshold string) bool {
+	for _, finding := range findings {
+		if !finding.Suppressed && AtLeast(finding.Severity, threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyThreshold re-derives the PASS/FAIL status of every validated file
+// against the threshold. Skipped files and files that could not be validated
+// keep their status.
+func ApplyThreshold(results []FileResult, threshold string) {
+	for i := range results {
+		if results[i].Error != "" || results[i].Status == StatusSkipped {
+			continue
+		}
+		results[i].Status = StatusPass
+		if Exceeds(results[i].Findings, threshold) {
+			results[i].Status = StatusFail
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// model/path.go
+package model
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// RelativePath returns file relative to root with forward slashes, or file
+// itself in slash form when it lies outside root, so reports, ignore entries
+// and baselines agree on paths and stay portable between checkouts
+func RelativePath(file, root string) string {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(absRoot, absFile)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,9 @@
+package report
+
+import "embed"
+
+// assets holds the HTML report template, its styles and scripts, and the JSON
+// report schema
+//
+//go:embed assets/report.html.tmpl assets/report.css assets/report.js assets/report.schema.json
+var assets embed.FS
@@ -0,0 +1,64 @@
+(function () {
+  "use strict";
+
+  // Sort a table by the clicked column; numeric cells use data-sort values
+  function sortTable(th) {
+    var table = th.closest("table");
+    var index = Array.prototype.indexOf.call(th.parentNode.children, th);
+    var ascending = !th.classList.contains("asc");
+    table.querySelectorAll("th").forEach(function (h) { h.classList.remove("asc", "desc"); });
+    th.classList.add(ascending ? "asc" : "desc");
+
+    var body = table.tBodies[0];
+    var rows = Array.prototype.slice.call(body.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[index].getAttribute("data-sort") || a.cells[index].textContent.trim();
+      var y = b.cells[index].getAttribute("data-sort") || b.cells[index].textContent.trim();
+      var nx = parseFloat(x), ny = parseFloat(y);
+      var result = !isNaN(nx) && !isNaN(ny) ? nx - ny : x.localeCompare(y);
+      return ascending ? result : -result;
+    });
+    rows.forEach(function (row) { body.appendChild(row); });
+  }
+
+  // Show only the findings matching the text, severity and suppression filters
+  function applyFilters() {
+    var text = document.getElementById("filter-text").value.toLowerCase();
+    var severity = document.getElementById("filter-severity").value;
+    var rule = document.getElementById("filter-rule").value;
+    var showSuppressed = document.getElementById("filter-suppressed").checked;
+    var shown = 0;
+
+    document.querySelectorAll("#findings tbody tr").forEach(function (row) {
+      var visible =
+        (text === "" || row.textContent.toLowerCase().indexOf(text) !== -1) &&
+        (severity === "" || row.getAttribute("data-severity") === severity) &&
+        (rule === "" || row.getAttribute("data-rule") === rule) &&
+        (showSuppressed || row.getAttribute("data-suppressed") !== "true");
+      row.hidden = !visible;
+      if (visible) { shown++; }
+    });
+    document.getElementById("filter-count").textContent = shown + " shown";
+  }
+
+  document.addEventListener("DOMContentLoaded", function () {
+    document.querySelectorAll("th.sortable").forEach(function (th) {
+      th.addEventListener("click", function () { sortTable(th); });
+    });
+    ["filter-text", "filter-severity", "filter-rule", "filter-suppressed"].forEach(function (id) {
+      var element = document.getElementById(id);
+      if (element) {
+        element.addEventListener("input", applyFilters);
+        element.addEventListener("change", applyFilters);
+      }
+    });
+    // Open the drill-down of a file when following a link to it
+    document.querySelectorAll("a[data-file]").forEach(function (link) {
+      link.addEventListener("click", function () {
+        var target = document.getElementById(link.getAttribute("data-file"));
+        if (target) { target.open = true; }
+      });
+    });
+    if (document.getElementById("findings")) { applyFilters(); }
+  });
+})();
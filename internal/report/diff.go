@@ -0,0 +1,119 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// Diff is the comparison of two reports, matched by finding fingerprint
+type Diff struct {
+	Introduced []Finding `json:"introduced"` // In the current report only
+	Fixed      []Finding `json:"fixed"`      // In the previous report only
+	Unchanged  []Finding `json:"unchanged"`  // In both, as found in the current report
+}
+
+// Compare matches the findings of two reports by fingerprint. Suppressed
+// findings are treated as absent, so suppressing a finding counts as fixing it.
+// A fingerprint reported several times is matched as many times as it occurs
+// in both reports.
+func Compare(previous, current *Report) *Diff {
+	diff := &Diff{Introduced: []Finding{}, Fixed: []Finding{}, Unchanged: []Finding{}}
+
+	remaining := make(map[string][]Finding)
+	for _, finding := range activeFindings(previous) {
+		remaining[finding.Fingerprint] = append(remaining[finding.Fingerprint], finding)
+	}
+	for _, finding := range activeFindings(current) {
+		if matches := remaining[finding.Fingerprint]; len(matches) > 0 {
+			remaining[finding.Fingerprint] = matches[1:]
+			diff.Unchanged = append(diff.Unchanged, finding)
+		} else {
+			diff.Introduced = append(diff.Introduced, finding)
+		}
+	}
+	for _, finding := range activeFindings(previous) {
+		if matches := remaining[finding.Fingerprint]; len(matches) > 0 {
+			remaining[finding.Fingerprint] = matches[1:]
+			diff.Fixed = append(diff.Fixed, finding)
+		}
+	}
+	return diff
+}
+
+// IntroducedAtLeast reports whether a finding of the given severity, or a more
+// severe one, was introduced
+func (d *Diff) IntroducedAtLeast(severity string) bool {
+	for _, finding := range d.Introduced {
+		if model.AtLeast(finding.Severity, severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON renders the diff as indented JSON
+func (d *Diff) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report diff: %w", err)
+	}
+	return data, nil
+}
+
+// Markdown renders the diff for a pull request comment: the introduced and
+// fixed findings as tables, and the unchanged ones folded away
+func (d *Diff) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Scan comparison\n\n")
+	fmt.Fprintf(&b, "**%d introduced**, %d fixed, %d unchanged\n", len(d.Introduced), len(d.Fixed), len(d.Unchanged))
+
+	writeTable := func(title string, findings []Finding) {
+		if len(findings) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", title)
+		b.WriteString("| Severity | Rule | Location | Message |\n|---|---|---|---|\n")
+		for _, finding := range findings {
+			location := finding.File
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, finding.Line)
+			}
+			if finding.Resource != "" {
+				location += " (" + finding.Resource + ")"
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` | %s |\n", finding.Severity, finding.RuleID, location, markdownCell(finding.Message))
+		}
+	}
+	writeTable("Introduced", d.Introduced)
+	writeTable("Fixed", d.Fixed)
+
+	if len(d.Unchanged) > 0 {
+		fmt.Fprintf(&b, "\n<details><summary>%d unchanged findings</summary>\n\n", len(d.Unchanged))
+		for _, finding := range d.Unchanged {
+			fmt.Fprintf(&b, "- `%s` %s: %s\n", finding.RuleID, finding.File, markdownCell(finding.Message))
+		}
+		b.WriteString("\n</details>\n")
+	}
+	return b.String()
+}
+
+// activeFindings returns the findings of a report that are not suppressed
+func activeFindings(report *Report) []Finding {
+	var findings []Finding
+	for _, file := range report.Files {
+		for _, finding := range file.Findings {
+			if !finding.Suppressed {
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
+
+// markdownCell escapes text for use in a Markdown table cell
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(text)
+}
@@ -0,0 +1,257 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// snippetContext is the number of lines shown around an offending line
+const snippetContext = 3
+
+type htmlReport struct {
+	Version    string
+	Root       string
+	Generated  string
+	Totals     htmlTotals
+	Summaries  []htmlSummary
+	Severities []string
+	RuleIDs    []string
+	Findings   []htmlFinding
+	Files      []htmlFile
+	CSS        template.CSS
+	JS         template.JS
+}
+
+type htmlTotals struct {
+	Files, Passed, Failed, Skipped, Findings, Suppressed int
+}
+
+type htmlSummary struct {
+	Title  string
+	Counts []htmlCount
+}
+
+type htmlCount struct {
+	Name  string
+	Count int
+}
+
+type htmlFile struct {
+	Path      string
+	Anchor    string
+	Status    string
+	Validator string
+	Error     string
+	Findings  []htmlFinding
+}
+
+type htmlFinding struct {
+	RuleID        string
+	Severity      string
+	Rank          int
+	File          string
+	FileAnchor    string
+	Line          int
+	Resource      string
+	Message       string
+	Suppressed    bool
+	Justification string
+	Remediation   string
+	Snippet       []htmlLine
+}
+
+type htmlLine struct {
+	Number    int
+	Text      string
+	Highlight bool
+}
+
+// HTML renders results as a single self-contained HTML page with a summary by
+// severity, rule and directory, a sortable and filterable findings table, and
+// a drill-down per file with the source around each finding and remediation
+// advice. Styles and scripts are inlined so the page works offline.
+func HTML(results []model.FileResult, root, version string, generated time.Time) ([]byte, error) {
+	page, err := assets.ReadFile("assets/report.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML template: %w", err)
+	}
+	css, err := assets.ReadFile("assets/report.css")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML styles: %w", err)
+	}
+	js, err := assets.ReadFile("assets/report.js")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML scripts: %w", err)
+	}
+	tmpl, err := template.New("report").Parse(string(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	data := buildHTMLReport(results, root)
+	data.Version = version
+	data.Generated = generated.Format(time.RFC1123)
+	data.CSS = template.CSS(css)
+	data.JS = template.JS(js)
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// buildHTMLReport collects the totals, summaries, findings and file sections
+func buildHTMLReport(results []model.FileResult, root string) htmlReport {
+	data := htmlReport{Root: root}
+	bySeverity := make(map[string]int)
+	byRule := make(map[string]int)
+	byDirectory := make(map[string]int)
+	sources := make(map[string][]string)
+
+	for i, result := range results {
+		file := htmlFile{
+			Path:      model.RelativePath(result.File, root),
+			Anchor:    fmt.Sprintf("file-%d", i),
+			Status:    result.Status,
+			Validator: result.Validator,
+			Error:     result.Error,
+		}
+		data.Totals.Files++
+		switch result.Status {
+		case model.StatusPass:
+			data.Totals.Passed++
+		case model.StatusFail:
+			data.Totals.Failed++
+		case model.StatusSkipped:
+			data.Totals.Skipped++
+		}
+
+		for _, finding := range result.Findings {
+			source := finding.File
+			if source == "" {
+				source = result.File
+			}
+			entry := htmlFinding{
+				RuleID:        finding.RuleID,
+				Severity:      finding.Severity,
+				Rank:          severityRank(finding.Severity),
+				File:          model.RelativePath(source, root),
+				FileAnchor:    file.Anchor,
+				Line:          finding.Line,
+				Resource:      finding.Resource,
+				Message:       finding.Message,
+				Suppressed:    finding.Suppressed,
+				Justification: finding.Justification,
+				Remediation:   Rule(finding.RuleID).Remediation,
+				Snippet:       snippet(sources, source, finding.Line),
+			}
+			file.Findings = append(file.Findings, entry)
+			data.Findings = append(data.Findings, entry)
+
+			if finding.Suppressed {
+				data.Totals.Suppressed++
+				continue
+			}
+			data.Totals.Findings++
+			bySeverity[finding.Severity]++
+			byRule[finding.RuleID]++
+			byDirectory[path.Dir(entry.File)]++
+		}
+		data.Files = append(data.Files, file)
+	}
+
+	data.Summaries = []htmlSummary{
+		{Title: "Severity", Counts: sortedCounts(bySeverity)},
+		{Title: "Rule", Counts: sortedCounts(byRule)},
+		{Title: "Directory", Counts: sortedCounts(byDirectory)},
+	}
+	for _, finding := range data.Findings {
+		data.Severities = appendUnique(data.Severities, finding.Severity)
+		data.RuleIDs = appendUnique(data.RuleIDs, finding.RuleID)
+	}
+	sort.Slice(data.Severities, func(i, j int) bool {
+		return severityRank(data.Severities[i]) < severityRank(data.Severities[j])
+	})
+	sort.Strings(data.RuleIDs)
+	sort.SliceStable(data.Findings, func(i, j int) bool { return data.Findings[i].Rank < data.Findings[j].Rank })
+	return data
+}
+
+// snippet returns the lines of file around line, reading each file once.
+// It returns nil when the line is unknown or the file cannot be read.
+func snippet(sources map[string][]string, file string, line int) []htmlLine {
+	if line <= 0 {
+		return nil
+	}
+	lines, ok := sources[file]
+	if !ok {
+		lines = readLines(file)
+		sources[file] = lines
+	}
+	if line > len(lines) {
+		return nil
+	}
+
+	start := max(line-snippetContext, 1)
+	end := min(line+snippetContext, len(lines))
+	var snippet []htmlLine
+	for number := start; number <= end; number++ {
+		snippet = append(snippet, htmlLine{Number: number, Text: lines[number-1], Highlight: number == line})
+	}
+	return snippet
+}
+
+// readLines returns the lines of a file, or nil when it cannot be read
+func readLines(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// severityRank orders severities from most (0) to least severe
+func severityRank(severity string) int {
+	return len(model.Severities) - 1 - model.SeverityRank(severity)
+}
+
+// sortedCounts returns the counts ordered by descending count, then name
+func sortedCounts(counts map[string]int) []htmlCount {
+	var sorted []htmlCount
+	for name, count := range counts {
+		sorted = append(sorted, htmlCount{Name: name, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// appendUnique appends value unless values already contains it
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mtyiska/scanrunner/internal/baseline"
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// SchemaVersion is the version of the JSON report format. The minor version
+// changes when optional fields are added, the major version when a field is
+// removed or changes meaning.
+const SchemaVersion = "1.0"
+
+// schemaMajor is the prefix of the report versions Load accepts
+const schemaMajor = "1."
+
+// SchemaID identifies the JSON Schema describing the report
+const SchemaID = "https://github.com/mtyiska/scanrunner/schemas/report-v1.schema.json"
+
+// Report is the JSON report of one scan
+type Report struct {
+	Schema        string    `json:"$schema"`
+	SchemaVersion string    `json:"schema_version"`
+	Tool          Tool      `json:"tool"`
+	ConfigHash    string    `json:"config_hash"` // Hash of the effective configuration
+	RulesHash     string    `json:"rules_hash"`  // Hash of the rules the files were validated with
+	ScanRoot      string    `json:"scan_root"`   // Directory file paths are relative to
+	StartedAt     time.Time `json:"started_at"`  // When validation started
+	FinishedAt    time.Time `json:"finished_at"` // When validation finished
+	Totals        Totals    `json:"totals"`      // Counts across all files
+	Files         []File    `json:"files"`       // One entry per validated file
+}
+
+// Tool identifies the scanner that produced the report
+type Tool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Totals summarises a report. Suppressed findings are counted separately and
+// excluded from the per-severity and per-rule counts.
+type Totals struct {
+	Files      int            `json:"files"`
+	Passed     int            `json:"passed"`
+	Failed     int            `json:"failed"`
+	Skipped    int            `json:"skipped"`
+	Findings   int            `json:"findings"`
+	Suppressed int            `json:"suppressed"`
+	BySeverity map[string]int `json:"by_severity"`
+	ByRule     map[string]int `json:"by_rule"`
+}
+
+// File is the outcome of validating one file
+type File struct {
+	Path      string    `json:"path"` // Relative to the scan root
+	Validator string    `json:"validator,omitempty"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Findings  []Finding `json:"findings"`
+}
+
+// Finding is a finding with its file relative to the scan root and the
+// line-independent fingerprint used to match it across scans
+type Finding struct {
+	model.Finding
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Metadata describes the scan a report is built for
+type Metadata struct {
+	Version    string    // Tool version
+	ConfigHash string    // See Hash
+	RulesHash  string    // See Hash
+	StartedAt  time.Time // When validation started
+	FinishedAt time.Time // When validation finished
+}
+
+// New builds the report for results, with paths relative to root
+func New(results []model.FileResult, root string, meta Metadata) *Report {
+	report := &Report{
+		Schema:        SchemaID,
+		SchemaVersion: SchemaVersion,
+		Tool:          Tool{Name: "scanrunner", Version: meta.Version},
+		ConfigHash:    meta.ConfigHash,
+		RulesHash:     meta.RulesHash,
+		ScanRoot:      root,
+		StartedAt:     meta.StartedAt.UTC(),
+		FinishedAt:    meta.FinishedAt.UTC(),
+		Totals:        Totals{BySeverity: map[string]int{}, ByRule: map[string]int{}},
+		Files:         []File{},
+	}
+
+	for _, result := range results {
+		file := File{
+			Path:      model.RelativePath(result.File, root),
+			Validator: result.Validator,
+			Status:    result.Status,
+			Error:     result.Error,
+			Findings:  []Finding{},
+		}
+		report.Totals.Files++
+		switch result.Status {
+		case model.StatusPass:
+			report.Totals.Passed++
+		case model.StatusFail:
+			report.Totals.Failed++
+		case model.StatusSkipped:
+			report.Totals.Skipped++
+		}
+
+		for _, finding := range result.Findings {
+			if finding.File == "" {
+				finding.File = result.File
+			}
+			fingerprint := baseline.Fingerprint(finding, root)
+			finding.File = model.RelativePath(finding.File, root)
+			file.Findings = append(file.Findings, Finding{Finding: finding, Fingerprint: fingerprint})
+
+			if finding.Suppressed {
+				report.Totals.Suppressed++
+				continue
+			}
+			report.Totals.Findings++
+			report.Totals.BySeverity[finding.Severity]++
+			report.Totals.ByRule[finding.RuleID]++
+		}
+		report.Files = append(report.Files, file)
+	}
+	return report
+}
+
+// JSON renders the report as indented JSON
+func (r *Report) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+	return data, nil
+}
+
+// Load parses a JSON report, rejecting reports from an incompatible schema
+func Load(data []byte) (*Report, error) {
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+	if !strings.HasPrefix(report.SchemaVersion, schemaMajor) {
+		return nil, fmt.Errorf("unsupported report schema version %q (want %sx)", report.SchemaVersion, schemaMajor)
+	}
+	return &report, nil
+}
+
+// Schema returns the JSON Schema describing the report format
+func Schema() ([]byte, error) {
+	return assets.ReadFile("assets/report.schema.json")
+}
+
+// Hash returns a stable "sha256:<hex>" digest of the JSON encoding of v,
+// used to record the configuration and rules a report was produced with
+func Hash(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit renders results as JUnit XML. Each file is a test suite named by its
+// path relative to root, and each rule applicable to the file's validator is a
+// test case: it fails when the rule reported a finding at or above threshold,
+// is skipped when all of its findings are suppressed and passes otherwise, with
+// lower-severity findings in its output. Files that could not be validated
+// report a single errored case.
+func JUnit(results []model.FileResult, root, threshold string) ([]byte, error) {
+	suites := junitTestSuites{Name: "scanrunner"}
+	for _, result := range results {
+		suite := junitSuiteOf(result, root, threshold)
+		suites.Suites = append(suites.Suites, suite)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// junitSuiteOf builds the test suite for one file
+func junitSuiteOf(result model.FileResult, root, threshold string) junitTestSuite {
+	name := model.RelativePath(result.File, root)
+	suite := junitTestSuite{Name: name}
+
+	switch {
+	case result.Status == model.StatusSkipped:
+		suite.TestCases = []junitTestCase{{Name: "validate", ClassName: name, Skipped: &junitMessage{Message: result.Error}}}
+	case result.Error != "":
+		suite.TestCases = []junitTestCase{{Name: "validate", ClassName: name, Error: &junitMessage{Message: result.Error, Type: "error"}}}
+	default:
+		suite.TestCases = junitRuleCases(result, name, root, threshold)
+	}
+
+	for _, testCase := range suite.TestCases {
+		suite.Tests++
+		switch {
+		case testCase.Failure != nil:
+			suite.Failures++
+		case testCase.Error != nil:
+			suite.Errors++
+		case testCase.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	return suite
+}
+
+// junitRuleCases returns one test case per rule applicable to the file, plus
+// one for every other rule that reported a finding
+func junitRuleCases(result model.FileResult, name, root, threshold string) []junitTestCase {
+	byRule := make(map[string][]model.Finding)
+	var ruleIDs []string
+	for _, info := range RulesFor(result.Validator) {
+		ruleIDs = append(ruleIDs, info.ID)
+		byRule[info.ID] = nil
+	}
+	for _, finding := range result.Findings {
+		if _, ok := byRule[finding.RuleID]; !ok {
+			ruleIDs = append(ruleIDs, finding.RuleID)
+		}
+		byRule[finding.RuleID] = append(byRule[finding.RuleID], finding)
+	}
+
+	var cases []junitTestCase
+	for _, ruleID := range ruleIDs {
+		testCase := junitTestCase{Name: ruleID, ClassName: name}
+		var failures, suppressed, warnings []string
+		failureMessage, failureSeverity := "", ""
+		for _, finding := range byRule[ruleID] {
+			line := junitLine(result.File, finding, root)
+			switch {
+			case finding.Suppressed:
+				suppressed = append(suppressed, fmt.Sprintf("%s (suppressed: %s)", line, finding.Justification))
+			case model.AtLeast(finding.Severity, threshold):
+				if failureMessage == "" || model.SeverityRank(finding.Severity) > model.SeverityRank(failureSeverity) {
+					failureMessage, failureSeverity = finding.Message, finding.Severity
+				}
+				failures = append(failures, line)
+			default:
+				warnings = append(warnings, fmt.Sprintf("%s (%s)", line, finding.Severity))
+			}
+		}
+
+		switch {
+		case len(failures) > 0:
+			testCase.Failure = &junitMessage{
+				Message: failureMessage,
+				Type:    failureSeverity,
+				Text:    strings.Join(failures, "\n"),
+			}
+		case len(suppressed) > 0 && len(warnings) == 0:
+			testCase.Skipped = &junitMessage{Message: strings.Join(suppressed, "\n")}
+		default:
+			testCase.SystemOut = strings.Join(append(warnings, suppressed...), "\n")
+		}
+		cases = append(cases, testCase)
+	}
+	return cases
+}
+
+// junitLine renders a finding as "path:line: message"
+func junitLine(file string, finding model.Finding, root string) string {
+	if finding.File != "" {
+		file = finding.File
+	}
+	location := model.RelativePath(file, root)
+	if finding.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, finding.Line)
+	}
+	return fmt.Sprintf("%s: %s", location, finding.Message)
+}
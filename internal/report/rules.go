@@ -0,0 +1,90 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/mtyiska/scanrunner/internal/suppression"
+	"github.com/mtyiska/scanrunner/internal/terraform"
+)
+
+// RuleInfo describes a rule for report consumers
+type RuleInfo struct {
+	ID          string // Stable rule identifier
+	Validator   string // Validator that applies the rule; empty for rules any validator may report
+	Description string // What the rule checks
+	Remediation string // How to fix a violation
+}
+
+// catalog holds the description and remediation of every built-in rule
+var catalog = map[string]RuleInfo{
+	"required-field":       {Validator: "kubernetes", Description: "Manifests must set the fields required by the rules file", Remediation: "Add the missing field to the manifest, or remove it from required_fields in the rules file if it does not apply."},
+	"pss-security-context": {Validator: "kubernetes", Description: "Containers must define a securityContext", Remediation: "Add a securityContext to every container, setting at least runAsNonRoot and allowPrivilegeEscalation: false."},
+	"pss-run-as-non-root":  {Validator: "kubernetes", Description: "Containers must run as a non-root user", Remediation: "Set securityContext.runAsNonRoot: true and build the image with a non-root USER."},
+	"network-policy":       {Validator: "kubernetes", Description: "Workloads should be covered by a NetworkPolicy", Remediation: "Add a NetworkPolicy selecting the workload that only allows the traffic it needs."},
+
+	"DL3020":                      {Validator: "dockerfile", Description: "Use COPY instead of ADD for files and folders", Remediation: "Replace ADD with COPY; use curl or wget in a RUN step when fetching remote files."},
+	"DL3007":                      {Validator: "dockerfile", Description: "Base images must be pinned to a version", Remediation: "Replace ':latest' or an untagged image with a specific version tag or digest."},
+	"apt-get-update":              {Validator: "dockerfile", Description: "apt-get install must follow apt-get update", Remediation: "Run 'apt-get update && apt-get install' in the same RUN instruction."},
+	"dockerignore-syntax":         {Validator: "dockerfile", Description: ".dockerignore patterns must be valid", Remediation: "Fix or remove the invalid pattern in .dockerignore."},
+	"dockerignore-sensitive-path": {Validator: "dockerfile", Description: "Secrets must not be sent to the build context", Remediation: "Add the sensitive path to .dockerignore, or copy only the files the image needs."},
+
+	"compose-privileged":       {Validator: "compose", Description: "Services must not run privileged", Remediation: "Remove 'privileged: true' and grant only the capabilities the service needs with cap_add."},
+	"compose-host-network":     {Validator: "compose", Description: "Services must not share the host network", Remediation: "Remove 'network_mode: host' and publish the required ports instead."},
+	"compose-host-pid":         {Validator: "compose", Description: "Services must not share the host PID namespace", Remediation: "Remove 'pid: host'."},
+	"compose-docker-socket":    {Validator: "compose", Description: "Services must not mount the Docker socket", Remediation: "Remove the docker.sock volume, or use a socket proxy that only exposes the required API calls."},
+	"compose-image-tag":        {Validator: "compose", Description: "Service images must be pinned to a version", Remediation: "Use a specific version tag or digest instead of ':latest' or no tag."},
+	"compose-env-secret":       {Validator: "compose", Description: "Secrets must not be hard-coded in the environment", Remediation: "Use Compose secrets or ${VARIABLE} interpolation from an untracked .env file."},
+	"compose-healthcheck":      {Validator: "compose", Description: "Services should define a healthcheck", Remediation: "Add a healthcheck so dependants can wait for the service to be ready."},
+	"compose-build-dockerfile": {Validator: "compose", Description: "Dockerfiles built by Compose must pass the Dockerfile checks", Remediation: "Fix the findings reported for the referenced Dockerfile."},
+
+	"gha-broad-permissions":  {Validator: "github-actions", Description: "Workflows should restrict GITHUB_TOKEN permissions", Remediation: "Set a top-level 'permissions' block with the minimum scopes, e.g. 'contents: read'."},
+	"gha-unpinned-action":    {Validator: "github-actions", Description: "Third-party actions must be pinned to a commit SHA", Remediation: "Replace the tag or branch with the full commit SHA, keeping the version in a comment."},
+	"gha-pr-target-checkout": {Validator: "github-actions", Description: "pull_request_target workflows must not check out untrusted code", Remediation: "Use the pull_request trigger, or do not check out the pull request head in a privileged workflow."},
+	"gha-script-injection":   {Validator: "github-actions", Description: "Untrusted expressions must not be expanded in run scripts", Remediation: "Pass the expression through an env variable and reference the variable in the script."},
+	"gha-secret-echo":        {Validator: "github-actions", Description: "Secrets must not be printed to the build log", Remediation: "Remove the command printing the secret."},
+
+	"tf-s3-public-acl":          {Validator: "terraform", Remediation: "Use the private ACL and grant access through bucket policies."},
+	"tf-s3-public-access-block": {Validator: "terraform", Remediation: "Set block_public_acls, block_public_policy, ignore_public_acls and restrict_public_buckets to true."},
+	"tf-sg-open-ingress":        {Validator: "terraform", Remediation: "Restrict cidr_blocks to known ranges, or put the service behind a load balancer."},
+	"tf-unencrypted-storage":    {Validator: "terraform", Remediation: "Enable encryption at rest (encrypted, storage_encrypted or kms_key_id)."},
+	"tf-iam-wildcard-action":    {Validator: "terraform", Remediation: "List the specific actions the principal needs instead of '*'."},
+
+	suppression.RuleID: {Description: "Suppressions must give a justification", Remediation: "Add a reason to the suppression, or remove it."},
+}
+
+func init() {
+	// Terraform rules describe themselves
+	for _, rule := range terraform.Rules {
+		info := catalog[rule.ID]
+		if info.Description == "" {
+			info.Description = rule.Description
+		}
+		catalog[rule.ID] = info
+	}
+}
+
+// RulesFor returns the catalog entries applicable to files claimed by a
+// validator, sorted by ID. Rendered Helm charts and Kustomize overlays are
+// checked with the Kubernetes rules.
+func RulesFor(validator string) []RuleInfo {
+	if validator == "helm" || validator == "kustomize" {
+		validator = "kubernetes"
+	}
+	var rules []RuleInfo
+	for id, info := range catalog {
+		if info.Validator == validator {
+			info.ID = id
+			rules = append(rules, info)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// Rule returns the catalog entry for a rule ID. Unknown rules get an entry
+// with only the ID set.
+func Rule(id string) RuleInfo {
+	info := catalog[id]
+	info.ID = id
+	return info
+}
@@ -0,0 +1,227 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/baseline"
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// SARIF 2.1.0 constants
+const (
+	sarifSchema      = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion     = "2.1.0"
+	sarifSourceRoot  = "%SRCROOT%"
+	sarifFingerprint = "scanrunner/v1"
+	informationURI   = "https://github.com/mtyiska/scanrunner"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Invocations        []sarifInvocation                `json:"invocations"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     *sarifMessage      `json:"shortDescription,omitempty"`
+	Help                 *sarifMessage      `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]string  `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+// SARIF renders results as a SARIF 2.1.0 log for code scanning tools. File
+// locations are relative to root, and every result carries the line-independent
+// baseline fingerprint so that alerts are tracked across edits. Files that
+// could not be validated are reported as tool execution notifications.
+func SARIF(results []model.FileResult, root, version string) ([]byte, error) {
+	driver := sarifDriver{Name: "scanrunner", Version: version, InformationURI: informationURI, Rules: []sarifRule{}}
+	ruleIndex := make(map[string]int)
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+	sarifResults := []sarifResult{}
+
+	for _, result := range results {
+		if result.Status == model.StatusFail && result.Error != "" {
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:     "error",
+				Message:   sarifMessage{Text: result.Error},
+				Locations: []sarifLocation{sarifLocationOf(result.File, 0, root)},
+			})
+		}
+
+		for _, finding := range result.Findings {
+			file := finding.File
+			if file == "" {
+				file = result.File
+			}
+			index, ok := ruleIndex[finding.RuleID]
+			if !ok {
+				index = len(driver.Rules)
+				ruleIndex[finding.RuleID] = index
+				driver.Rules = append(driver.Rules, sarifRuleOf(finding))
+			}
+
+			fingerprintFinding := finding
+			fingerprintFinding.File = file
+			sarifResult := sarifResult{
+				RuleID:              finding.RuleID,
+				RuleIndex:           index,
+				Level:               sarifLevel(finding.Severity),
+				Message:             sarifMessage{Text: finding.Message},
+				Locations:           []sarifLocation{sarifLocationOf(file, finding.Line, root)},
+				PartialFingerprints: map[string]string{sarifFingerprint: baseline.Fingerprint(fingerprintFinding, root)},
+			}
+			if finding.Suppressed {
+				sarifResult.Suppressions = []sarifSuppression{{Kind: "inSource", Justification: finding.Justification}}
+			}
+			sarifResults = append(sarifResults, sarifResult)
+		}
+	}
+
+	run := sarifRun{
+		Tool:        sarifTool{Driver: driver},
+		Invocations: []sarifInvocation{invocation},
+		Results:     sarifResults,
+	}
+	if absRoot, err := filepath.Abs(root); err == nil {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifSourceRoot: {URI: "file://" + strings.TrimSuffix(filepath.ToSlash(absRoot), "/") + "/"},
+		}
+	}
+
+	data, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SARIF report: %w", err)
+	}
+	return data, nil
+}
+
+// sarifRuleOf builds the catalog entry for the rule that produced finding
+func sarifRuleOf(finding model.Finding) sarifRule {
+	info := Rule(finding.RuleID)
+	rule := sarifRule{
+		ID:                   info.ID,
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(finding.Severity)},
+		Properties:           map[string]string{"security-severity": securitySeverity(finding.Severity)},
+	}
+	if info.Description != "" {
+		rule.ShortDescription = &sarifMessage{Text: info.Description}
+	}
+	if info.Remediation != "" {
+		rule.Help = &sarifMessage{Text: info.Remediation}
+	}
+	return rule
+}
+
+// sarifLocationOf returns the location of a file, and of a line within it when known
+func sarifLocationOf(file string, line int, root string) sarifLocation {
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: model.RelativePath(file, root), URIBaseID: sarifSourceRoot},
+	}}
+	if line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+	return location
+}
+
+// sarifLevel maps a finding severity to a SARIF result level
+func sarifLevel(severity string) string {
+	switch severity {
+	case model.SeverityCritical, model.SeverityHigh:
+		return "error"
+	case model.SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// securitySeverity maps a finding severity to the CVSS-like score code
+// scanning tools use to rank security alerts
+func securitySeverity(severity string) string {
+	switch severity {
+	case model.SeverityCritical:
+		return "9.0"
+	case model.SeverityHigh:
+		return "7.0"
+	case model.SeverityMedium:
+		return "5.0"
+	case model.SeverityLow:
+		return "3.0"
+	default:
+		return "0.0"
+	}
+}
@@ -0,0 +1,205 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// ANSI escape sequences used by the terminal table
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+)
+
+// TableOptions controls how Table renders results
+type TableOptions struct {
+	Color     bool   // Emit ANSI colours
+	Quiet     bool   // Only show failing files and the findings that fail them
+	Threshold string // Lowest severity that fails a file
+}
+
+// Table renders results for a terminal: findings grouped by file, most severe
+// first, each followed by the offending source line with a caret, and a
+// summary footer. File paths are relative to root.
+func Table(results []model.FileResult, root string, opts TableOptions) string {
+	t := table{opts: opts, sources: make(map[string][]string)}
+	var b strings.Builder
+
+	bySeverity := make(map[string]int)
+	var passed, failed, skipped, findings, suppressed int
+	for _, result := range results {
+		switch result.Status {
+		case model.StatusPass:
+			passed++
+		case model.StatusFail:
+			failed++
+		case model.StatusSkipped:
+			skipped++
+		}
+		for _, finding := range result.Findings {
+			if finding.Suppressed {
+				suppressed++
+				continue
+			}
+			findings++
+			bySeverity[finding.Severity]++
+		}
+
+		if opts.Quiet && result.Status != model.StatusFail {
+			continue
+		}
+		t.writeFile(&b, result, root)
+	}
+
+	// Summary footer
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d files: %s, %s, %d skipped\n", len(results),
+		t.paint(ansiGreen, fmt.Sprintf("%d passed", passed)),
+		t.paint(ansiRed, fmt.Sprintf("%d failed", failed)), skipped)
+	var counts []string
+	for i := len(model.Severities) - 1; i >= 0; i-- {
+		severity := model.Severities[i]
+		if bySeverity[severity] > 0 {
+			counts = append(counts, t.paint(severityColor(severity), fmt.Sprintf("%d %s", bySeverity[severity], severity)))
+		}
+	}
+	fmt.Fprintf(&b, "%d findings", findings)
+	if len(counts) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(counts, ", "))
+	}
+	fmt.Fprintf(&b, ", %d suppressed; failing at %s and above\n", suppressed, opts.Threshold)
+	return b.String()
+}
+
+// table holds the state shared while rendering one table
+type table struct {
+	opts    TableOptions
+	sources map[string][]string // Lines of each source file read so far
+}
+
+// writeFile writes the header of one file followed by its findings
+func (t table) writeFile(b *strings.Builder, result model.FileResult, root string) {
+	name := model.RelativePath(result.File, root)
+	fmt.Fprintf(b, "%s  %s\n", t.paint(ansiBold, name), t.status(result.Status))
+	if result.Error != "" {
+		fmt.Fprintf(b, "  %s\n", t.paint(ansiDim, result.Error))
+	}
+
+	var shown []model.Finding
+	for _, finding := range result.Findings {
+		if t.opts.Quiet && (finding.Suppressed || !model.AtLeast(finding.Severity, t.opts.Threshold)) {
+			continue
+		}
+		shown = append(shown, finding)
+	}
+	sort.SliceStable(shown, func(i, j int) bool {
+		if shown[i].Suppressed != shown[j].Suppressed {
+			return !shown[i].Suppressed
+		}
+		if rank := model.SeverityRank(shown[i].Severity) - model.SeverityRank(shown[j].Severity); rank != 0 {
+			return rank > 0
+		}
+		return shown[i].Line < shown[j].Line
+	})
+
+	ruleWidth := 0
+	for _, finding := range shown {
+		ruleWidth = max(ruleWidth, len(finding.RuleID))
+	}
+	for _, finding := range shown {
+		source := finding.File
+		if source == "" {
+			source = result.File
+		}
+		var where []string
+		if source != result.File {
+			where = append(where, model.RelativePath(source, root))
+		}
+		if finding.Line > 0 {
+			where = append(where, fmt.Sprintf("line %d", finding.Line))
+		}
+		if finding.Resource != "" {
+			where = append(where, "("+finding.Resource+")")
+		}
+		location := ""
+		if len(where) > 0 {
+			location = strings.Join(where, " ") + ": "
+		}
+
+		message := finding.Message
+		if finding.Suppressed {
+			message += " (suppressed: " + finding.Justification + ")"
+		}
+		severity := fmt.Sprintf("%-8s", strings.ToUpper(finding.Severity))
+		rule := fmt.Sprintf("%-*s", ruleWidth, finding.RuleID)
+		if finding.Suppressed {
+			fmt.Fprintf(b, "  %s\n", t.paint(ansiDim, severity+"  "+rule+"  "+location+message))
+			continue
+		}
+		fmt.Fprintf(b, "  %s  %s  %s%s\n", t.paint(severityColor(finding.Severity), severity), rule, location, message)
+		t.writeSource(b, source, finding.Line)
+	}
+}
+
+// writeSource writes the source line a finding points at, with a caret under
+// its first non-blank character. Nothing is written when the line is unknown
+// or the file cannot be read.
+func (t table) writeSource(b *strings.Builder, file string, line int) {
+	lines := snippet(t.sources, file, line)
+	for _, l := range lines {
+		if !l.Highlight {
+			continue
+		}
+		text := strings.ReplaceAll(l.Text, "\t", "    ")
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+		gutter := fmt.Sprintf("%d", l.Number)
+		fmt.Fprintf(b, "      %s | %s\n", t.paint(ansiDim, gutter), text)
+		fmt.Fprintf(b, "      %s | %s%s\n", strings.Repeat(" ", len(gutter)), strings.Repeat(" ", indent), t.paint(ansiRed, "^"))
+	}
+}
+
+// status renders a file status
+func (t table) status(status string) string {
+	switch status {
+	case model.StatusPass:
+		return t.paint(ansiGreen, status)
+	case model.StatusFail:
+		return t.paint(ansiRed+ansiBold, status)
+	default:
+		return t.paint(ansiDim, status)
+	}
+}
+
+// paint wraps text in an ANSI style when colours are enabled
+func (t table) paint(style, text string) string {
+	if !t.opts.Color {
+		return text
+	}
+	return style + text + ansiReset
+}
+
+// severityColor returns the ANSI style for a severity
+func severityColor(severity string) string {
+	switch severity {
+	case model.SeverityCritical:
+		return ansiRed + ansiBold
+	case model.SeverityHigh:
+		return ansiRed
+	case model.SeverityMedium:
+		return ansiYellow
+	case model.SeverityLow:
+		return ansiBlue
+	default:
+		return ansiDim
+	}
+}
@@ -0,0 +1,60 @@
+package suppression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// RuleID identifies the finding reported for a suppression without a justification
+const RuleID = "suppression-justification"
+
+// Suppression silences a set of rules, with the reason it is acceptable
+type Suppression struct {
+	Rules  []string // Rule IDs to silence
+	Reason string   // Justification recorded on suppressed findings
+}
+
+// ParseRules splits a comma-separated list of rule IDs, dropping empty entries
+func ParseRules(list string) []string {
+	var rules []string
+	for _, rule := range strings.Split(list, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Matches reports whether the suppression covers the rule
+func (s Suppression) Matches(ruleID string) bool {
+	for _, rule := range s.Rules {
+		if rule == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply marks the findings covered by the suppression as suppressed, keeping
+// them in the list for audit. Only findings accepted by inScope are considered.
+func (s Suppression) Apply(findings []model.Finding, inScope func(model.Finding) bool) {
+	for i := range findings {
+		if findings[i].Suppressed || !s.Matches(findings[i].RuleID) || !inScope(findings[i]) {
+			continue
+		}
+		findings[i].Suppressed = true
+		findings[i].Justification = s.Reason
+	}
+}
+
+// MissingReason returns the finding reported when a suppression gives no
+// justification; the suppression is not applied in that case.
+func MissingReason(rules []string) model.Finding {
+	return model.Finding{
+		RuleID:   RuleID,
+		Severity: model.SeverityLow,
+		Message:  fmt.Sprintf("suppression of %s ignored: a justification is required", strings.Join(rules, ", ")),
+	}
+}
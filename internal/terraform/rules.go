@@ -0,0 +1,235 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Rule is a Terraform misconfiguration check applied to resource or data blocks
+type Rule struct {
+	ID            string                         // Stable rule identifier
+	Severity      string                         // Severity of the findings it reports
+	Description   string                         // What the rule checks
+	BlockType     string                         // "resource" or "data"
+	ResourceTypes []string                       // Provider resource types the rule applies to
+	Check         func(*hclsyntax.Block) []issue // Returns the problems found in a block
+}
+
+// issue is a problem found by a rule check, before it becomes a finding
+type issue struct {
+	line    int
+	snippet string // Distinguishes several issues of one rule in the same block
+	message string
+}
+
+// publicCIDRs are the ranges that expose a port to the whole internet
+var publicCIDRs = []string{"0.0.0.0/0", "::/0"}
+
+// Rules is the starter rule pack for common AWS misconfigurations
+var Rules = []Rule{
+	{
+		ID:            "tf-s3-public-acl",
+		Severity:      model.SeverityCritical,
+		Description:   "S3 buckets must not use a public canned ACL",
+		BlockType:     "resource",
+		ResourceTypes: []string{"aws_s3_bucket", "aws_s3_bucket_acl"},
+		Check:         checkPublicACL,
+	},
+	{
+		ID:            "tf-s3-public-access-block",
+		Severity:      model.SeverityHigh,
+		Description:   "S3 public access blocks must enable every protection",
+		BlockType:     "resource",
+		ResourceTypes: []string{"aws_s3_bucket_public_access_block", "aws_s3_account_public_access_block"},
+		Check:         checkPublicAccessBlock,
+	},
+	{
+		ID:            "tf-sg-open-ingress",
+		Severity:      model.SeverityHigh,
+		Description:   "Security groups must not allow ingress from 0.0.0.0/0 or ::/0",
+		BlockType:     "resource",
+		ResourceTypes: []string{"aws_security_group", "aws_security_group_rule", "aws_vpc_security_group_ingress_rule"},
+		Check:         checkOpenIngress,
+	},
+	{
+		ID:            "tf-unencrypted-storage",
+		Severity:      model.SeverityHigh,
+		Description:   "Block, database and file storage must be encrypted at rest",
+		BlockType:     "resource",
+		ResourceTypes: []string{"aws_ebs_volume", "aws_db_instance", "aws_rds_cluster", "aws_efs_file_system"},
+		Check:         checkEncryption,
+	},
+	{
+		ID:            "tf-iam-wildcard-action",
+		Severity:      model.SeverityHigh,
+		Description:   "IAM policies must not allow every action ('*')",
+		BlockType:     "resource",
+		ResourceTypes: []string{"aws_iam_policy", "aws_iam_role_policy", "aws_iam_user_policy", "aws_iam_group_policy"},
+		Check:         checkPolicyWildcard,
+	},
+	{
+		ID:            "tf-iam-wildcard-action",
+		Severity:      model.SeverityHigh,
+		Description:   "IAM policy documents must not allow every action ('*')",
+		BlockType:     "data",
+		ResourceTypes: []string{"aws_iam_policy_document"},
+		Check:         checkPolicyDocumentWildcard,
+	},
+}
+
+// checkPublicACL flags public-read and public-read-write canned ACLs
+func checkPublicACL(block *hclsyntax.Block) []issue {
+	value, attr, ok := attributeValue(block.Body, "acl")
+	if !ok || value.Type() != cty.String {
+		return nil
+	}
+	switch acl := value.AsString(); acl {
+	case "public-read", "public-read-write":
+		return []issue{{line: attr.SrcRange.Start.Line, message: fmt.Sprintf("canned ACL '%s' makes the bucket public", acl)}}
+	}
+	return nil
+}
+
+// checkPublicAccessBlock flags protections that are set to false or left
+// unset, since every protection defaults to false
+func checkPublicAccessBlock(block *hclsyntax.Block) []issue {
+	var issues []issue
+	for _, name := range []string{"block_public_acls", "block_public_policy", "ignore_public_acls", "restrict_public_buckets"} {
+		if _, exists := block.Body.Attributes[name]; !exists {
+			issues = append(issues, issue{line: block.TypeRange.Start.Line, snippet: name, message: fmt.Sprintf("%s is not set; it defaults to false", name)})
+			continue
+		}
+		value, attr, ok := attributeValue(block.Body, name)
+		if ok && value.Type() == cty.Bool && value.False() {
+			issues = append(issues, issue{line: attr.SrcRange.Start.Line, snippet: name, message: fmt.Sprintf("%s is disabled", name)})
+		}
+	}
+	return issues
+}
+
+// checkOpenIngress flags ingress rules open to the internet, whether written
+// as inline ingress blocks or as standalone rule resources
+func checkOpenIngress(block *hclsyntax.Block) []issue {
+	var issues []issue
+	openCIDRs := func(body *hclsyntax.Body) {
+		for _, name := range []string{"cidr_blocks", "ipv6_cidr_blocks", "cidr_ipv4", "cidr_ipv6"} {
+			value, attr, ok := attributeValue(body, name)
+			if !ok {
+				continue
+			}
+			for _, cidr := range stringValues(value) {
+				if contains(publicCIDRs, cidr) {
+					issues = append(issues, issue{line: attr.SrcRange.Start.Line, snippet: name + " = " + cidr, message: fmt.Sprintf("ingress is open to %s", cidr)})
+				}
+			}
+		}
+	}
+
+	switch block.Labels[0] {
+	case "aws_security_group":
+		for _, nested := range block.Body.Blocks {
+			if nested.Type == "ingress" {
+				openCIDRs(nested.Body)
+			}
+		}
+	case "aws_security_group_rule":
+		if value, _, ok := attributeValue(block.Body, "type"); ok && value.Type() == cty.String && value.AsString() == "ingress" {
+			openCIDRs(block.Body)
+		}
+	default:
+		openCIDRs(block.Body)
+	}
+	return issues
+}
+
+// checkEncryption flags storage without encryption at rest enabled
+func checkEncryption(block *hclsyntax.Block) []issue {
+	name := "encrypted"
+	if block.Labels[0] == "aws_db_instance" || block.Labels[0] == "aws_rds_cluster" {
+		name = "storage_encrypted"
+	}
+	if _, exists := block.Body.Attributes[name]; !exists {
+		return []issue{{line: block.TypeRange.Start.Line, message: fmt.Sprintf("%s is not set; storage is unencrypted by default", name)}}
+	}
+	value, attr, ok := attributeValue(block.Body, name)
+	if ok && value.Type() == cty.Bool && value.False() {
+		return []issue{{line: attr.SrcRange.Start.Line, message: fmt.Sprintf("%s is false", name)}}
+	}
+	return nil
+}
+
+// checkPolicyWildcard flags Allow statements granting "*" in a JSON policy,
+// written either as a string or with jsonencode()
+func checkPolicyWildcard(block *hclsyntax.Block) []issue {
+	value, attr, ok := attributeValue(block.Body, "policy")
+	if !ok || value.Type() != cty.String {
+		return nil
+	}
+
+	var policy struct {
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(value.AsString()), &policy); err != nil {
+		return nil
+	}
+	var statements []map[string]interface{}
+	if err := json.Unmarshal(policy.Statement, &statements); err != nil {
+		var single map[string]interface{}
+		if err := json.Unmarshal(policy.Statement, &single); err != nil {
+			return nil
+		}
+		statements = []map[string]interface{}{single}
+	}
+
+	for _, statement := range statements {
+		if effect, _ := statement["Effect"].(string); effect != "Allow" {
+			continue
+		}
+		if hasWildcard(statement["Action"]) {
+			return []issue{{line: attr.SrcRange.Start.Line, message: "policy allows every action ('*')"}}
+		}
+	}
+	return nil
+}
+
+// checkPolicyDocumentWildcard flags aws_iam_policy_document statements whose
+// actions include "*"
+func checkPolicyDocumentWildcard(block *hclsyntax.Block) []issue {
+	var issues []issue
+	for _, statement := range block.Body.Blocks {
+		if statement.Type != "statement" {
+			continue
+		}
+		if value, _, ok := attributeValue(statement.Body, "effect"); ok && value.Type() == cty.String && value.AsString() != "Allow" {
+			continue
+		}
+		value, attr, ok := attributeValue(statement.Body, "actions")
+		if ok && contains(stringValues(value), "*") {
+			var sid string
+			if value, _, ok := attributeValue(statement.Body, "sid"); ok && value.Type() == cty.String {
+				sid = value.AsString()
+			}
+			issues = append(issues, issue{line: attr.SrcRange.Start.Line, snippet: sid, message: "statement allows every action ('*')"})
+		}
+	}
+	return issues
+}
+
+// hasWildcard reports whether a JSON Action value is or includes "*"
+func hasWildcard(action interface{}) bool {
+	switch a := action.(type) {
+	case string:
+		return a == "*"
+	case []interface{}:
+		for _, item := range a {
+			if s, ok := item.(string); ok && s == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
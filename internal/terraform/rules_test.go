@@ -0,0 +1,160 @@
+package terraform
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // Rule ID and line of each finding, as "id:line"
+	}{
+		{
+			name:    "public canned ACL",
+			content: "resource \"aws_s3_bucket_acl\" \"logs\" {\n  acl = \"public-read\"\n}\n",
+			want:    []string{"tf-s3-public-acl:2"},
+		},
+		{
+			name:    "private canned ACL",
+			content: "resource \"aws_s3_bucket\" \"logs\" {\n  acl = \"private\"\n}\n",
+		},
+		{
+			name: "public access block fully enabled",
+			content: `resource "aws_s3_bucket_public_access_block" "logs" {
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
+`,
+		},
+		{
+			name: "public access block protection disabled",
+			content: `resource "aws_s3_bucket_public_access_block" "logs" {
+  block_public_acls       = false
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
+`,
+			want: []string{"tf-s3-public-access-block:2"},
+		},
+		{
+			name: "public access block protections unset",
+			content: `resource "aws_s3_account_public_access_block" "account" {
+  block_public_acls  = true
+  ignore_public_acls = true
+}
+`,
+			want: []string{"tf-s3-public-access-block:1", "tf-s3-public-access-block:1"},
+		},
+		{
+			name:    "empty public access block",
+			content: "resource \"aws_s3_bucket_public_access_block\" \"logs\" {\n}\n",
+			want:    []string{"tf-s3-public-access-block:1", "tf-s3-public-access-block:1", "tf-s3-public-access-block:1", "tf-s3-public-access-block:1"},
+		},
+		{
+			name: "public access block set from a variable",
+			content: `resource "aws_s3_bucket_public_access_block" "logs" {
+  block_public_acls       = var.block
+  block_public_policy     = var.block
+  ignore_public_acls      = var.block
+  restrict_public_buckets = var.block
+}
+`,
+		},
+		{
+			name: "inline ingress open to the internet",
+			content: `resource "aws_security_group" "web" {
+  ingress {
+    from_port   = 22
+    to_port     = 22
+    protocol    = "tcp"
+    cidr_blocks = ["10.0.0.0/8", "0.0.0.0/0"]
+  }
+}
+`,
+			want: []string{"tf-sg-open-ingress:6"},
+		},
+		{
+			name: "egress rule open to the internet",
+			content: `resource "aws_security_group_rule" "out" {
+  type        = "egress"
+  cidr_blocks = ["0.0.0.0/0"]
+}
+`,
+		},
+		{
+			name:    "standalone ingress rule open over IPv6",
+			content: "resource \"aws_vpc_security_group_ingress_rule\" \"web\" {\n  cidr_ipv6 = \"::/0\"\n}\n",
+			want:    []string{"tf-sg-open-ingress:2"},
+		},
+		{
+			name:    "volume without encryption",
+			content: "resource \"aws_ebs_volume\" \"data\" {\n  size = 10\n}\n",
+			want:    []string{"tf-unencrypted-storage:1"},
+		},
+		{
+			name:    "database with encryption disabled",
+			content: "resource \"aws_db_instance\" \"main\" {\n  storage_encrypted = false\n}\n",
+			want:    []string{"tf-unencrypted-storage:2"},
+		},
+		{
+			name:    "encrypted file system",
+			content: "resource \"aws_efs_file_system\" \"shared\" {\n  encrypted = true\n}\n",
+		},
+		{
+			name: "policy allowing every action",
+			content: `resource "aws_iam_policy" "admin" {
+  policy = jsonencode({
+    Statement = [{ Effect = "Allow", Action = "*", Resource = "*" }]
+  })
+}
+`,
+			want: []string{"tf-iam-wildcard-action:2"},
+		},
+		{
+			name: "policy denying every action",
+			content: `resource "aws_iam_role_policy" "guard" {
+  policy = jsonencode({
+    Statement = { Effect = "Deny", Action = ["*"], Resource = "*" }
+  })
+}
+`,
+		},
+		{
+			name: "policy document allowing every action",
+			content: `data "aws_iam_policy_document" "admin" {
+  statement {
+    sid     = "All"
+    actions = ["*"]
+  }
+  statement {
+    effect  = "Deny"
+    actions = ["*"]
+  }
+}
+`,
+			want: []string{"tf-iam-wildcard-action:4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := ValidateTerraformFile("main.tf", []byte(tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, finding := range findings {
+				got = append(got, fmt.Sprintf("%s:%d", finding.RuleID, finding.Line))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// evalContext resolves literal expressions and the encoding functions commonly
+// used to build policy documents. References to variables, locals and other
+// resources stay unknown and are ignored by the rules.
+var evalContext = &hcl.EvalContext{
+	Functions: map[string]function.Function{
+		"jsonencode": stdlib.JSONEncodeFunc,
+		"jsondecode": stdlib.JSONDecodeFunc,
+	},
+}
+
+// Validator validates Terraform configuration files against the starter rule pack.
+type Validator struct{}
+
+// Name returns the validator name used in results
+func (Validator) Name() string { return "terraform" }
+
+// Detect reports whether the file is a Terraform configuration file
+func (Validator) Detect(filePath string, content []byte) bool {
+	return strings.ToLower(filepath.Ext(filePath)) == ".tf"
+}
+
+// Validate parses the HCL and returns the findings of every rule in Rules
+func (Validator) Validate(ctx context.Context, filePath string, content []byte, rules model.Rules) ([]model.Finding, error) {
+	return ValidateTerraformFile(filePath, content)
+}
+
+// ValidateTerraformFile parses Terraform HCL and runs every rule in Rules
+// against each resource and data block.
+func ValidateTerraformFile(filePath string, content []byte) ([]model.Finding, error) {
+	file, diags := hclsyntax.ParseConfig(content, filePath, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, &model.InputError{Err: fmt.Errorf("failed to parse HCL: %s", diags.Error())}
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unexpected HCL body type %T", file.Body)
+	}
+
+	var findings []model.Finding
+	for _, block := range body.Blocks {
+		if (block.Type != "resource" && block.Type != "data") || len(block.Labels) != 2 {
+			continue
+		}
+		for _, rule := range Rules {
+			if rule.BlockType != block.Type || !contains(rule.ResourceTypes, block.Labels[0]) {
+				continue
+			}
+			for _, issue := range rule.Check(block) {
+				findings = append(findings, model.Finding{
+					RuleID:   rule.ID,
+					Severity: rule.Severity,
+					File:     filePath,
+					Line:     issue.line,
+					Resource: block.Labels[0] + "." + block.Labels[1],
+					Snippet:  issue.snippet,
+					Message:  fmt.Sprintf("%s.%s: %s", block.Labels[0], block.Labels[1], issue.message),
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// attributeValue evaluates an attribute of a block. It returns false when the
+// attribute is missing or its value cannot be known statically.
+func attributeValue(body *hclsyntax.Body, name string) (cty.Value, *hclsyntax.Attribute, bool) {
+	attr, exists := body.Attributes[name]
+	if !exists {
+		return cty.NilVal, nil, false
+	}
+	value, diags := attr.Expr.Value(evalContext)
+	if diags.HasErrors() || !value.IsWhollyKnown() || value.IsNull() {
+		return cty.NilVal, attr, false
+	}
+	return value, attr, true
+}
+
+// stringValues flattens a string or a list/set/tuple of strings
+func stringValues(value cty.Value) []string {
+	switch {
+	case value.Type() == cty.String:
+		return []string{value.AsString()}
+	case value.CanIterateElements():
+		var values []string
+		for it := value.ElementIterator(); it.Next(); {
+			_, element := it.Element()
+			if element.IsKnown() && !element.IsNull() && element.Type() == cty.String {
+				values = append(values, element.AsString())
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// contains reports whether list includes value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
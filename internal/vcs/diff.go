@@ -0,0 +1,102 @@
+package vcs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineRange is an inclusive range of 1-based line numbers
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// hunkHeader captures the start line and length of the new side of a hunk
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff parses a unified diff, as produced by `git diff`, and
+// returns for each file on the new side the ranges of added or modified
+// lines. Paths are taken from the "+++ b/" headers with the prefix removed.
+// The second map marks files the diff creates.
+func ParseUnifiedDiff(r io.Reader) (map[string][]LineRange, map[string]bool, error) {
+	lines := make(map[string][]LineRange)
+	created := make(map[string]bool)
+
+	var file string
+	newFile := false
+	newLine := 0
+	inHunk := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			file, newFile, inHunk = "", false, false
+		case !inHunk && strings.HasPrefix(text, "new file mode"):
+			newFile = true
+		case !inHunk && strings.HasPrefix(text, "+++ "):
+			file = diffPath(strings.TrimPrefix(text, "+++ "))
+			if file != "" && newFile {
+				created[file] = true
+			}
+		case strings.HasPrefix(text, "@@"):
+			match := hunkHeader.FindStringSubmatch(text)
+			if match == nil {
+				return nil, nil, fmt.Errorf("malformed hunk header: %q", text)
+			}
+			newLine, _ = strconv.Atoi(match[1])
+			inHunk = true
+		case inHunk && strings.HasPrefix(text, "+"):
+			if file != "" {
+				lines[file] = addLine(lines[file], newLine)
+			}
+			newLine++
+		case inHunk && strings.HasPrefix(text, " "):
+			newLine++
+		}
+		// "-" lines and "\ No newline at end of file" do not advance the new side
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+	return lines, created, nil
+}
+
+// InRanges reports whether line falls inside any of the ranges
+func InRanges(ranges []LineRange, line int) bool {
+	for _, r := range ranges {
+		if line >= r.Start && line <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// diffPath extracts the path from a "+++" header value, unquoting paths git
+// escaped and dropping the "b/" prefix. Deleted files (/dev/null) yield "".
+func diffPath(header string) string {
+	if strings.HasPrefix(header, `"`) {
+		if unquoted, err := strconv.Unquote(header); err == nil {
+			header = unquoted
+		}
+	}
+	if header == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(header, "b/")
+}
+
+// addLine appends line to ranges, extending the last range when contiguous
+func addLine(ranges []LineRange, line int) []LineRange {
+	if n := len(ranges); n > 0 && ranges[n-1].End == line-1 {
+		ranges[n-1].End = line
+		return ranges
+	}
+	return append(ranges, LineRange{Start: line, End: line})
+}
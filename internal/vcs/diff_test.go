@@ -0,0 +1,151 @@
+package vcs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name        string
+		diff        string
+		wantLines   map[string][]LineRange
+		wantCreated map[string]bool
+	}{
+		{
+			name: "modified lines",
+			diff: `diff --git a/deploy.yaml b/deploy.yaml
+index 1111111..2222222 100644
+--- a/deploy.yaml
++++ b/deploy.yaml
+@@ -3 +3,2 @@ metadata:
+-  name: web
++  name: api
++  namespace: prod
+@@ -10,0 +12 @@ spec:
++  replicas: 3
+`,
+			wantLines:   map[string][]LineRange{"deploy.yaml": {{Start: 3, End: 4}, {Start: 12, End: 12}}},
+			wantCreated: map[string]bool{},
+		},
+		{
+			name: "pure deletion hunk",
+			diff: `diff --git a/deploy.yaml b/deploy.yaml
+index 1111111..2222222 100644
+--- a/deploy.yaml
++++ b/deploy.yaml
+@@ -5,2 +4,0 @@ metadata:
+-  labels:
+-    app: web
+`,
+			wantLines:   map[string][]LineRange{},
+			wantCreated: map[string]bool{},
+		},
+		{
+			name: "deletion hunk before an addition",
+			diff: `diff --git a/deploy.yaml b/deploy.yaml
+index 1111111..2222222 100644
+--- a/deploy.yaml
++++ b/deploy.yaml
+@@ -2,3 +1,0 @@
+-a
+-b
+-c
+@@ -9,0 +7,2 @@ spec:
++  replicas: 3
++  paused: false
+`,
+			wantLines:   map[string][]LineRange{"deploy.yaml": {{Start: 7, End: 8}}},
+			wantCreated: map[string]bool{},
+		},
+		{
+			name: "rename with changes",
+			diff: `diff --git a/old/app.yaml b/new/app.yaml
+similarity index 90%
+rename from old/app.yaml
+rename to new/app.yaml
+index 1111111..2222222 100644
+--- a/old/app.yaml
++++ b/new/app.yaml
+@@ -1 +1 @@
+-apiVersion: v1
++apiVersion: apps/v1
+`,
+			wantLines:   map[string][]LineRange{"new/app.yaml": {{Start: 1, End: 1}}},
+			wantCreated: map[string]bool{},
+		},
+		{
+			name: "pure rename",
+			diff: `diff --git a/old/app.yaml b/new/app.yaml
+similarity index 100%
+rename from old/app.yaml
+rename to new/app.yaml
+`,
+			wantLines:   map[string][]LineRange{},
+			wantCreated: map[string]bool{},
+		},
+		{
+			name: "new file",
+			diff: `diff --git a/svc.yaml b/svc.yaml
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/svc.yaml
+@@ -0,0 +1,2 @@
++apiVersion: v1
++kind: Service
+`,
+			wantLines:   map[string][]LineRange{"svc.yaml": {{Start: 1, End: 2}}},
+			wantCreated: map[string]bool{"svc.yaml": true},
+		},
+		{
+			name: "deleted file",
+			diff: `diff --git a/svc.yaml b/svc.yaml
+deleted file mode 100644
+index 3333333..0000000
+--- a/svc.yaml
++++ /dev/null
+@@ -1,2 +0,0 @@
+-apiVersion: v1
+-kind: Service
+`,
+			wantLines:   map[string][]LineRange{},
+			wantCreated: map[string]bool{},
+		},
+		{
+			name: "quoted path and added line resembling a header",
+			diff: `diff --git "a/my app.yaml" "b/my app.yaml"
+index 1111111..2222222 100644
+--- "a/my app.yaml"
++++ "b/my app.yaml"
+@@ -1,0 +2 @@
++++ not a header
+`,
+			wantLines:   map[string][]LineRange{"my app.yaml": {{Start: 2, End: 2}}},
+			wantCreated: map[string]bool{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, created, err := ParseUnifiedDiff(strings.NewReader(tt.diff))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("lines = %v, want %v", lines, tt.wantLines)
+			}
+			if !reflect.DeepEqual(created, tt.wantCreated) {
+				t.Errorf("created = %v, want %v", created, tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestParseUnifiedDiffMalformedHunk(t *testing.T) {
+	diff := "diff --git a/x.yaml b/x.yaml\n--- a/x.yaml\n+++ b/x.yaml\n@@ bogus @@\n"
+	if _, _, err := ParseUnifiedDiff(strings.NewReader(diff)); err == nil {
+		t.Error("expected an error for a malformed hunk header")
+	}
+}
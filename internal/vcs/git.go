@@ -0,0 +1,200 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChangeSet is the set of files changed in a Git repository. A nil ChangeSet
+// means "no restriction": every path is considered changed.
+type ChangeSet struct {
+	files   map[string]bool        // Absolute, cleaned paths
+	lines   map[string][]LineRange // Added or modified lines per file
+	created map[string]bool        // Files added by the change
+}
+
+// ChangedSince returns the files changed between the merge base of ref and
+// HEAD and the working tree, so committed, staged and unstaged edits on the
+// current branch are all included, as are untracked files not ignored by
+// .gitignore. Deleted files are not reported.
+func ChangedSince(ctx context.Context, dir, ref string) (*ChangeSet, error) {
+	mergeBase, err := git(ctx, dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base with %s: %w", ref, err)
+	}
+	changes, err := diffNames(ctx, dir, strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	if err := addUntracked(ctx, dir, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Staged returns the files staged in the index
+func Staged(ctx context.Context, dir string) (*ChangeSet, error) {
+	return diffNames(ctx, dir, "--cached")
+}
+
+// Contains reports whether path is part of the change set
+func (c *ChangeSet) Contains(path string) bool {
+	if c == nil {
+		return true
+	}
+	return c.files[absolute(path)]
+}
+
+// ContainsUnder reports whether any changed file lies inside dir
+func (c *ChangeSet) ContainsUnder(dir string) bool {
+	if c == nil {
+		return true
+	}
+	return len(c.Under(dir)) > 0
+}
+
+// Under returns the changed files inside dir, as absolute paths in sorted order
+func (c *ChangeSet) Under(dir string) []string {
+	if c == nil {
+		return nil
+	}
+	prefix := absolute(dir) + string(filepath.Separator)
+	var files []string
+	for file := range c.files {
+		if strings.HasPrefix(file, prefix) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// ContainsLine reports whether line of path was added or modified. Findings
+// without a line (0), such as those on rendered Helm or Kustomize output,
+// count as changed when the file has any added or modified line.
+func (c *ChangeSet) ContainsLine(path string, line int) bool {
+	if c == nil {
+		return true
+	}
+	path = absolute(path)
+	if c.created[path] {
+		return true
+	}
+	if line == 0 {
+		return len(c.lines[path]) > 0
+	}
+	return InRanges(c.lines[path], line)
+}
+
+// Filter returns the files that are part of the change set, preserving order
+func (c *ChangeSet) Filter(files []string) []string {
+	if c == nil {
+		return files
+	}
+	var filtered []string
+	for _, file := range files {
+		if c.Contains(file) {
+			filtered = append(filtered, file)
+		}
+	}
+	return filtered
+}
+
+// diffNames lists the added, copied, modified, renamed and type-changed files
+// reported by `git diff` with the given argument, along with their changed lines
+func diffNames(ctx context.Context, dir, arg string) (*ChangeSet, error) {
+	root, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not a Git repository: %w", err)
+	}
+	root = strings.TrimSpace(root)
+
+	out, err := git(ctx, dir, "diff", "--name-only", "--diff-filter=ACMRT", "-z", arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+
+	changes := &ChangeSet{
+		files:   make(map[string]bool),
+		lines:   make(map[string][]LineRange),
+		created: make(map[string]bool),
+	}
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			changes.files[filepath.Join(root, filepath.FromSlash(name))] = true
+		}
+	}
+
+	patch, err := git(ctx, dir, "diff", "--unified=0", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", "--diff-filter=ACMRT", arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+	lines, created, err := ParseUnifiedDiff(strings.NewReader(patch))
+	if err != nil {
+		return nil, err
+	}
+	for name, ranges := range lines {
+		changes.lines[filepath.Join(root, filepath.FromSlash(name))] = ranges
+	}
+	for name := range created {
+		changes.created[filepath.Join(root, filepath.FromSlash(name))] = true
+	}
+	return changes, nil
+}
+
+// addUntracked adds the untracked, non-ignored files of the repository to
+// changes as new files, so every line of them counts as changed
+func addUntracked(ctx context.Context, dir string, changes *ChangeSet) error {
+	root, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("not a Git repository: %w", err)
+	}
+	root = strings.TrimSpace(root)
+
+	// ":/" lists the whole repository even when dir is a subdirectory
+	out, err := git(ctx, dir, "ls-files", "--others", "--exclude-standard", "--full-name", "-z", ":/")
+	if err != nil {
+		return fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			path := filepath.Join(root, filepath.FromSlash(name))
+			changes.files[path] = true
+			changes.created[path] = true
+		}
+	}
+	return nil
+}
+
+// git runs a git subcommand in dir and returns its standard output
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.Error); ok {
+			return "", fmt.Errorf("git is not installed or not in PATH")
+		}
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// absolute returns the cleaned absolute form of path, resolving symlinks so
+// paths match those reported by git
+func absolute(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
@@ -0,0 +1,136 @@
+package vcs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newRepo creates a Git repository with one commit holding files
+func newRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, files)
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if _, err := git(context.Background(), dir, args...); err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+}
+
+func TestChangedSince(t *testing.T) {
+	dir := newRepo(t, map[string]string{
+		".gitignore":      "build/\n",
+		"deploy.yaml":     "a: 1\nb: 2\nc: 3\n",
+		"old/app.yaml":    "x: 1\ny: 2\nz: 3\nw: 4\n",
+		"removed.yaml":    "gone: true\n",
+		"unchanged.yaml":  "same: true\n",
+		"sub/nested.yaml": "n: 1\n",
+	})
+
+	// Modify one line, rename a file with a small edit, delete a file and
+	// leave new files untracked, one of them ignored
+	writeFiles(t, dir, map[string]string{
+		"deploy.yaml":    "a: 1\nb: 20\nc: 3\n",
+		"sub/fresh.yaml": "f: 1\n",
+		"build/out.yaml": "ignored: true\n",
+	})
+	runGit(t, dir, "mv", "old/app.yaml", "new-app.yaml")
+	writeFiles(t, dir, map[string]string{"new-app.yaml": "x: 1\ny: 2\nz: 3\nw: 40\n"})
+	runGit(t, dir, "rm", "-q", "removed.yaml")
+
+	// Run from a subdirectory to check untracked files elsewhere are found
+	changes, err := ChangedSince(context.Background(), filepath.Join(dir, "sub"), "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file    string
+		line    int
+		changed bool
+	}{
+		{"deploy.yaml", 2, true},
+		{"deploy.yaml", 1, false},
+		{"deploy.yaml", 0, true},
+		{"new-app.yaml", 4, true},
+		{"new-app.yaml", 1, false},
+		{"sub/fresh.yaml", 0, true},
+		{"sub/fresh.yaml", 1, true},
+		{"unchanged.yaml", 1, false},
+		{"unchanged.yaml", 0, false},
+		{"removed.yaml", 1, false},
+		{"build/out.yaml", 1, false},
+		{"sub/nested.yaml", 1, false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, filepath.FromSlash(tt.file))
+		if got := changes.ContainsLine(path, tt.line); got != tt.changed {
+			t.Errorf("ContainsLine(%s, %d) = %t, want %t", tt.file, tt.line, got, tt.changed)
+		}
+	}
+	for _, file := range []string{"deploy.yaml", "new-app.yaml", "sub/fresh.yaml"} {
+		if !changes.Contains(filepath.Join(dir, file)) {
+			t.Errorf("Contains(%s) = false, want true", file)
+		}
+	}
+	for _, file := range []string{"removed.yaml", "unchanged.yaml", "build/out.yaml", "old/app.yaml"} {
+		if changes.Contains(filepath.Join(dir, file)) {
+			t.Errorf("Contains(%s) = true, want false", file)
+		}
+	}
+}
+
+func TestStagedExcludesUntracked(t *testing.T) {
+	dir := newRepo(t, map[string]string{"deploy.yaml": "a: 1\n"})
+	writeFiles(t, dir, map[string]string{
+		"deploy.yaml":    "a: 2\n",
+		"untracked.yaml": "u: 1\n",
+	})
+	runGit(t, dir, "add", "deploy.yaml")
+
+	changes, err := Staged(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changes.ContainsLine(filepath.Join(dir, "deploy.yaml"), 1) {
+		t.Error("staged line not reported")
+	}
+	if changes.Contains(filepath.Join(dir, "untracked.yaml")) {
+		t.Error("untracked file reported as staged")
+	}
+}
@@ -0,0 +1,269 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/internal/fileparser"
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+var (
+	// pinnedRef matches a full-length commit SHA after "@"
+	pinnedRef = regexp.MustCompile(`@[0-9a-f]{40}$`)
+	// untrustedExpression matches expressions expanding attacker-controlled event data
+	untrustedExpression = regexp.MustCompile(`\$\{\{\s*(github\.event\.[\w.\[\]'"*-]+|github\.head_ref)\s*}}`)
+	// secretExpression matches expressions expanding a secret
+	secretExpression = regexp.MustCompile(`\$\{\{\s*secrets\.[\w-]+\s*}}`)
+	// echoCommand matches shell commands that print their arguments
+	echoCommand = regexp.MustCompile(`\b(echo|printf|cat\s*<<)\b`)
+	// prHeadRef matches checkout refs pointing at the pull request head
+	prHeadRef = regexp.MustCompile(`github\.event\.pull_request\.head\.(sha|ref)|github\.head_ref`)
+)
+
+// Validator validates GitHub Actions workflow files.
+type Validator struct{}
+
+// Name returns the validator name used in results
+func (Validator) Name() string { return "github-actions" }
+
+// Detect reports whether the file is a GitHub Actions workflow, either by its
+// location under .github/workflows or by top-level "on" and "jobs" keys
+func (Validator) Detect(filePath string, content []byte) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if ext != ".yaml" && ext != ".yml" {
+		return false
+	}
+	if strings.Contains(filepath.ToSlash(filePath), ".github/workflows/") {
+		return true
+	}
+	parsedData, err := fileparser.ParseYAMLContent(content)
+	if err != nil {
+		return false
+	}
+	_, hasJobs := parsedData["jobs"]
+	return hasJobs && triggers(parsedData) != nil
+}
+
+// Validate parses the workflow and returns its findings
+func (Validator) Validate(ctx context.Context, filePath string, content []byte, rules model.Rules) ([]model.Finding, error) {
+	parsedData, err := fileparser.ParseYAMLContent(content)
+	if err != nil {
+		return nil, &model.InputError{Err: fmt.Errorf("error parsing YAML file: %w", err)}
+	}
+	return ValidateWorkflow(filePath, content, parsedData)
+}
+
+// ValidateWorkflow checks a parsed workflow for supply-chain and injection risks
+func ValidateWorkflow(filePath string, content []byte, data map[string]interface{}) ([]model.Finding, error) {
+	jobs, ok := data["jobs"].(map[string]interface{})
+	if !ok {
+		return nil, &model.InputError{Err: errors.New("missing or invalid 'jobs' section")}
+	}
+
+	lines := strings.Split(string(content), "\n")
+	locate := func(path string) int { return fileparser.LocateLine(content, path) }
+	var findings []model.Finding
+	add := func(ruleID, severity, resource string, line int, snippet, message string) {
+		findings = append(findings, model.Finding{
+			RuleID:   ruleID,
+			Severity: severity,
+			File:     filePath,
+			Line:     line,
+			Resource: resource,
+			Snippet:  snippet,
+			Message:  message,
+		})
+	}
+
+	// checkPermissions reports write-all and every scope granted write access
+	// by the permissions value at path
+	checkPermissions := func(permissions interface{}, path, resource, prefix string) {
+		switch value := permissions.(type) {
+		case string:
+			if value == "write-all" {
+				add("gha-broad-permissions", model.SeverityHigh, resource, locate(path), "", prefix+"'permissions: write-all' grants every scope to the GITHUB_TOKEN")
+			}
+		case map[string]interface{}:
+			for _, scope := range writeScopes(value) {
+				snippet := scope + ": write"
+				add("gha-broad-permissions", model.SeverityMedium, resource, locate(path+"."+scope), snippet, fmt.Sprintf("%s'permissions' grant '%s' to the GITHUB_TOKEN; grant it only to the jobs that need it", prefix, snippet))
+			}
+		}
+	}
+
+	// Workflow-level permissions apply to every job that does not override them
+	if permissions, exists := data["permissions"]; !exists || permissions == nil {
+		add("gha-broad-permissions", model.SeverityLow, "", 0, "", "no top-level 'permissions' set; the GITHUB_TOKEN gets the repository default scopes")
+	} else {
+		checkPermissions(permissions, "permissions", "", "top-level ")
+	}
+	workflowSecretEnv := secretEnvNames(data["env"])
+
+	pullRequestTarget := false
+	for _, trigger := range triggers(data) {
+		if trigger == "pull_request_target" {
+			pullRequestTarget = true
+		}
+	}
+
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		job, ok := jobs[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		jobPath := "jobs." + name
+		checkPermissions(job["permissions"], jobPath+".permissions", "job/"+name, fmt.Sprintf("job '%s': ", name))
+		if uses, ok := job["uses"].(string); ok && !isPinned(uses) {
+			add("gha-unpinned-action", model.SeverityHigh, "job/"+name, locate(jobPath+".uses"), uses, fmt.Sprintf("job '%s': reusable workflow '%s' is not pinned to a commit SHA", name, uses))
+		}
+
+		secretEnv := append(secretEnvNames(job["env"]), workflowSecretEnv...)
+		steps, _ := job["steps"].([]interface{})
+		for i, item := range steps {
+			step, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			stepPath := fmt.Sprintf("%s.steps[%d]", jobPath, i)
+			stepSecretEnv := append(secretEnvNames(step["env"]), secretEnv...)
+
+			if uses, ok := step["uses"].(string); ok {
+				if !isPinned(uses) {
+					add("gha-unpinned-action", model.SeverityHigh, "job/"+name, locate(stepPath+".uses"), uses, fmt.Sprintf("job '%s': action '%s' is not pinned to a commit SHA", name, uses))
+				}
+				if pullRequestTarget && strings.HasPrefix(uses, "actions/checkout@") {
+					if with, ok := step["with"].(map[string]interface{}); ok {
+						if ref, ok := with["ref"].(string); ok && prHeadRef.MatchString(ref) {
+							add("gha-pr-target-checkout", model.SeverityCritical, "job/"+name, locate(stepPath+".with.ref"), ref, fmt.Sprintf("job '%s': pull_request_target workflow checks out the untrusted pull request head", name))
+						}
+					}
+				}
+			}
+
+			run, ok := step["run"].(string)
+			if !ok {
+				continue
+			}
+			runLine := locate(stepPath + ".run")
+			for _, match := range untrustedExpression.FindAllString(run, -1) {
+				add("gha-script-injection", model.SeverityCritical, "job/"+name, lineFrom(lines, runLine, match), match, fmt.Sprintf("job '%s': '%s' is expanded directly into a run script; pass it through an environment variable instead", name, match))
+			}
+			for _, line := range strings.Split(run, "\n") {
+				if !echoCommand.MatchString(line) {
+					continue
+				}
+				if secretExpression.MatchString(line) || referencesAny(line, stepSecretEnv) {
+					add("gha-secret-echo", model.SeverityHigh, "job/"+name, lineFrom(lines, runLine, strings.TrimSpace(line)), strings.TrimSpace(line), fmt.Sprintf("job '%s': a secret is printed to the build log", name))
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// triggers returns the event names a workflow runs on. YAML 1.1 parses the bare
+// key "on" as a boolean, so the "true" key is checked as well.
+func triggers(data map[string]interface{}) []string {
+	on, exists := data["on"]
+	if !exists {
+		on, exists = data["true"]
+	}
+	if !exists {
+		return nil
+	}
+
+	switch value := on.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var events []string
+		for _, event := range value {
+			events = append(events, fmt.Sprintf("%v", event))
+		}
+		return events
+	case map[string]interface{}:
+		var events []string
+		for event := range value {
+			events = append(events, event)
+		}
+		sort.Strings(events)
+		return events
+	}
+	return nil
+}
+
+// isPinned reports whether a "uses" reference is local, a digest-pinned
+// container, or pinned to a full commit SHA
+func isPinned(uses string) bool {
+	if strings.HasPrefix(uses, "./") {
+		return true
+	}
+	if strings.HasPrefix(uses, "docker://") {
+		return strings.Contains(uses, "@sha256:")
+	}
+	return pinnedRef.MatchString(uses)
+}
+
+// secretEnvNames returns the environment variables whose value is a secret
+func secretEnvNames(env interface{}) []string {
+	vars, ok := env.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var names []string
+	for name, value := range vars {
+		if s, ok := value.(string); ok && secretExpression.MatchString(s) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// writeScopes returns the sorted permission scopes granted write access
+func writeScopes(permissions map[string]interface{}) []string {
+	var scopes []string
+	for scope, access := range permissions {
+		if access == "write" {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// referencesAny reports whether a shell line expands any of the variables
+func referencesAny(line string, names []string) bool {
+	for _, name := range names {
+		if strings.Contains(line, "$"+name) || strings.Contains(line, "${"+name+"}") {
+			return true
+		}
+	}
+	return false
+}
+
+// lineFrom returns the 1-based line of the first occurrence of needle at or
+// after line start, which is the line of the step's run key. It returns start
+// when needle is not found there, such as in a folded or quoted script.
+func lineFrom(lines []string, start int, needle string) int {
+	if start <= 0 {
+		return 0
+	}
+	for i := start - 1; i < len(lines); i++ {
+		if strings.Contains(lines[i], needle) {
+			return i + 1
+		}
+	}
+	return start
+}
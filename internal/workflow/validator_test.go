@@ -0,0 +1,174 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mtyiska/scanrunner/internal/fileparser"
+)
+
+func TestValidateWorkflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string // Only findings of this rule are compared
+		content string
+		want    []string // Line and snippet of each finding, as "line:snippet"
+	}{
+		{
+			name:    "gha-broad-permissions without top-level permissions",
+			rule:    "gha-broad-permissions",
+			content: "on: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n",
+			want:    []string{"0:"},
+		},
+		{
+			name:    "gha-broad-permissions with top-level write-all",
+			rule:    "gha-broad-permissions",
+			content: "on: push\npermissions: write-all\njobs:\n  build:\n    runs-on: ubuntu-latest\n",
+			want:    []string{"2:"},
+		},
+		{
+			name: "gha-broad-permissions with top-level write scopes",
+			rule: "gha-broad-permissions",
+			content: `on: push
+permissions:
+  pull-requests: write
+  contents: write
+  issues: read
+jobs:
+  build:
+    runs-on: ubuntu-latest
+`,
+			want: []string{"4:contents: write", "3:pull-requests: write"},
+		},
+		{
+			name: "gha-broad-permissions with job-level write access",
+			rule: "gha-broad-permissions",
+			content: `on: push
+permissions:
+  contents: read
+jobs:
+  release:
+    permissions:
+      packages: write
+  deploy:
+    permissions: write-all
+`,
+			want: []string{"9:", "7:packages: write"},
+		},
+		{
+			name: "gha-broad-permissions with read-only access",
+			rule: "gha-broad-permissions",
+			content: `on: push
+permissions: read-all
+jobs:
+  build:
+    permissions:
+      contents: read
+`,
+		},
+		{
+			name: "gha-unpinned-action",
+			rule: "gha-unpinned-action",
+			content: `on: push
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@0aaccfd150d50ccaeb58ebd88d36e91967a5f35b
+      - uses: ./.github/actions/local
+      - uses: docker://alpine:3.20
+  shared:
+    uses: org/repo/.github/workflows/ci.yml@main
+`,
+			want: []string{"5:actions/checkout@v4", "8:docker://alpine:3.20", "10:org/repo/.github/workflows/ci.yml@main"},
+		},
+		{
+			name: "gha-pr-target-checkout",
+			rule: "gha-pr-target-checkout",
+			content: `on: pull_request_target
+jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+      - uses: actions/checkout@v4
+        with:
+          ref: main
+`,
+			want: []string{"7:${{ github.event.pull_request.head.sha }}"},
+		},
+		{
+			name: "gha-pr-target-checkout on pull_request",
+			rule: "gha-pr-target-checkout",
+			content: `on: pull_request
+jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+`,
+		},
+		{
+			name: "gha-script-injection",
+			rule: "gha-script-injection",
+			content: `on: issues
+jobs:
+  triage:
+    steps:
+      - env:
+          TITLE: ${{ github.event.issue.title }}
+        run: |
+          echo "$TITLE"
+          echo "${{ github.event.issue.body }}"
+`,
+			want: []string{"9:${{ github.event.issue.body }}"},
+		},
+		{
+			name: "gha-secret-echo",
+			rule: "gha-secret-echo",
+			content: `on: push
+env:
+  DEPLOY_TOKEN: ${{ secrets.DEPLOY_TOKEN }}
+jobs:
+  deploy:
+    env:
+      API_KEY: ${{ secrets.API_KEY }}
+    steps:
+      - env:
+          PASSWORD: ${{ secrets.PASSWORD }}
+        run: |
+          echo "$PASSWORD"
+          echo "${API_KEY}"
+          echo "$DEPLOY_TOKEN"
+          echo "${{ secrets.OTHER }}"
+          echo "$HOME"
+`,
+			want: []string{"12:echo \"$PASSWORD\"", "13:echo \"${API_KEY}\"", "14:echo \"$DEPLOY_TOKEN\"", "15:echo \"${{ secrets.OTHER }}\""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := fileparser.ParseYAMLContent([]byte(tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings, err := ValidateWorkflow("ci.yml", []byte(tt.content), data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, finding := range findings {
+				if finding.RuleID == tt.rule {
+					got = append(got, fmt.Sprintf("%d:%s", finding.Line, finding.Snippet))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
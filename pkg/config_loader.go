@@ -6,6 +6,8 @@ import (
 	"log"
 	"os"
 
+	"github.com/mtyiska/scanrunner/internal/ignore"
+	"github.com/mtyiska/scanrunner/internal/model"
 	"gopkg.in/yaml.v2"
 )
 
@@ -16,6 +18,12 @@ type Config struct {
 	RulesPath    string `yaml:"rules_path"`    // Path to rules file
 	ReportOutput string `yaml:"report_output"` // Path to save the report
 	StrictMode   bool   `yaml:"strict_mode"`   // Enable strict validation
+	FailOn       string `yaml:"fail_on"`       // Lowest severity that fails a file
+
+	DockerfilePatterns []string `yaml:"dockerfile_patterns"` // Extra file name globs treated as Dockerfiles
+	CacheDir           string   `yaml:"cache_dir"`           // Directory for cached validation results
+
+	Ignore ignore.List `yaml:"ignore"` // Path excludes and expiring suppressions, merged with .scanrunnerignore
 }
 
 // DefaultConfig provides default values for config.yaml
@@ -24,8 +32,9 @@ func DefaultConfig() Config {
 		OutputFormat: "json",
 		ScanPath:     "./example-files",
 		RulesPath:    "./custom-rules.yaml",
-		ReportOutput: "./report.md",
+		ReportOutput: "./report.json",
 		StrictMode:   false,
+		FailOn:       model.DefaultThreshold,
 	}
 }
 
@@ -36,18 +45,14 @@ func LoadConfig(configFile string) (Config, error) {
 
 	// Attempt to load the config file if provided
 	if configFile != "" {
-		file, err := os.Open(configFile)
+		data, err := os.ReadFile(configFile)
 		if err != nil {
 			log.Printf("Config file not found. Using defaults. Error: %v\n", err)
 			return config, nil // Return defaults if file is missing
 		}
-		defer file.Close()
-
-		decoder := yaml.NewDecoder(file)
-		decoder.SetStrict(false) // Allow unknown fields without errors
 
-		if err := decoder.Decode(&config); err != nil {
-			return Config{}, fmt.Errorf("failed to parse config file: %v", err)
+		if config, err = ParseConfig(data); err != nil {
+			return Config{}, err
 		}
 
 		// log.Printf("Config file loaded successfully: %+v\n", config)
@@ -70,6 +75,14 @@ func LoadConfig(configFile string) (Config, error) {
 		log.Printf("Overriding ReportOutput with environment variable: %s\n", val)
 		config.ReportOutput = val
 	}
+	if val, ok := os.LookupEnv("SCANRUNNER_CACHE_DIR"); ok {
+		log.Printf("Overriding CacheDir with environment variable: %s\n", val)
+		config.CacheDir = val
+	}
+	if val, ok := os.LookupEnv("SCANRUNNER_FAIL_ON"); ok {
+		log.Printf("Overriding FailOn with environment variable: %s\n", val)
+		config.FailOn = val
+	}
 	if val, ok := os.LookupEnv("SCANRUNNER_STRICT_MODE"); ok {
 		if val == "true" {
 			log.Printf("Overriding StrictMode with environment variable: true\n")
@@ -83,3 +96,15 @@ func LoadConfig(configFile string) (Config, error) {
 	// log.Printf("Final Config: %+v\n", config)
 	return config, nil
 }
+
+// ParseConfig parses the content of a configuration file. Fields it does not
+// set keep their defaults; environment variables are not consulted.
+func ParseConfig(data []byte) (Config, error) {
+	config := DefaultConfig()
+
+	// yaml.Unmarshal allows unknown fields without errors
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse config file: %v", err)
+	}
+	return config, nil
+}
@@ -24,19 +24,23 @@ func LoadRules(path string) (model.Rules, error) {
 		path = "./config/default-rules.yaml"
 	}
 
-	rules := DefaultRules() // Start with defaults
-
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		log.Printf("Rules file not found at %s. Using defaults. Error: %v\n", path, err)
-		return rules, nil // Return defaults if file is missing
+		return DefaultRules(), nil // Return defaults if file is missing
 	}
-	defer file.Close()
 
-	decoder := yaml.NewDecoder(file)
-	decoder.SetStrict(false) // Ignore unknown fields
+	// log.Printf("Rules loaded from %s\n", path)
+	return ParseRules(data)
+}
+
+// ParseRules parses and validates the content of a rules file. Fields it
+// does not set keep their defaults.
+func ParseRules(data []byte) (model.Rules, error) {
+	rules := DefaultRules() // Start with defaults
 
-	if err := decoder.Decode(&rules); err != nil {
+	// yaml.Unmarshal ignores unknown fields
+	if err := yaml.Unmarshal(data, &rules); err != nil {
 		return model.Rules{}, fmt.Errorf("failed to parse rules file: %w", err)
 	}
 
@@ -44,8 +48,6 @@ func LoadRules(path string) (model.Rules, error) {
 	if err := model.ValidateRules(rules); err != nil {
 		return model.Rules{}, fmt.Errorf("invalid rules file: %w", err)
 	}
-
-	// log.Printf("Rules loaded from %s: %+v\n", path, rules)
 	return rules, nil
 }
 
@@ -0,0 +1,103 @@
+package scanrunner
+
+import (
+	"github.com/mtyiska/scanrunner/internal/ignore"
+	"github.com/mtyiska/scanrunner/internal/model"
+	"github.com/mtyiska/scanrunner/pkg"
+)
+
+// The public types mirror internal ones so the internal packages can change
+// without breaking the API; these functions convert at the boundary.
+
+// configFrom converts a loaded configuration
+func configFrom(c pkg.Config) Config {
+	config := Config{
+		OutputFormat:       c.OutputFormat,
+		ScanPath:           c.ScanPath,
+		RulesPath:          c.RulesPath,
+		ReportOutput:       c.ReportOutput,
+		StrictMode:         c.StrictMode,
+		FailOn:             c.FailOn,
+		DockerfilePatterns: append([]string(nil), c.DockerfilePatterns...),
+		CacheDir:           c.CacheDir,
+		Ignore:             IgnoreList{Exclude: append([]string(nil), c.Ignore.Exclude...)},
+	}
+	for _, entry := range c.Ignore.Suppress {
+		config.Ignore.Suppress = append(config.Ignore.Suppress, Suppression{
+			Paths:     append([]string(nil), entry.Paths...),
+			Rules:     append([]string(nil), entry.Rules...),
+			Resources: append([]string(nil), entry.Resources...),
+			Owner:     entry.Owner,
+			Reason:    entry.Reason,
+			Expires:   entry.Expires,
+		})
+	}
+	return config
+}
+
+// internal converts an ignore list; it must be compiled before use
+func (l IgnoreList) internal() ignore.List {
+	list := ignore.List{Exclude: append([]string(nil), l.Exclude...)}
+	for _, s := range l.Suppress {
+		list.Suppress = append(list.Suppress, ignore.Entry{
+			Paths:     append([]string(nil), s.Paths...),
+			Rules:     append([]string(nil), s.Rules...),
+			Resources: append([]string(nil), s.Resources...),
+			Owner:     s.Owner,
+			Reason:    s.Reason,
+			Expires:   s.Expires,
+		})
+	}
+	return list
+}
+
+// rulesFrom converts loaded rules
+func rulesFrom(r model.Rules) Rules {
+	return Rules{RequiredFields: append([]string(nil), r.RequiredFields...)}
+}
+
+// internal converts rules for the validators
+func (r Rules) internal() model.Rules {
+	return model.Rules{RequiredFields: append([]string(nil), r.RequiredFields...)}
+}
+
+// findingFrom converts a finding reported by a validator
+func findingFrom(f model.Finding) Finding {
+	return Finding{
+		RuleID:        f.RuleID,
+		Severity:      f.Severity,
+		File:          f.File,
+		Line:          f.Line,
+		Resource:      f.Resource,
+		Path:          f.Path,
+		Snippet:       f.Snippet,
+		Message:       f.Message,
+		Suppressed:    f.Suppressed,
+		Justification: f.Justification,
+	}
+}
+
+// internal converts a finding reported by a custom rule
+func (f Finding) internal() model.Finding {
+	return model.Finding{
+		RuleID:        f.RuleID,
+		Severity:      f.Severity,
+		File:          f.File,
+		Line:          f.Line,
+		Resource:      f.Resource,
+		Path:          f.Path,
+		Snippet:       f.Snippet,
+		Message:       f.Message,
+		Suppressed:    f.Suppressed,
+		Justification: f.Justification,
+	}
+}
+
+// resultFrom converts the result of validating a file
+func resultFrom(r model.FileResult) FileResult {
+	result := FileResult{File: r.File, Validator: r.Validator, Status: r.Status, Error: r.Error}
+	for _, finding := range r.Findings {
+		result.Findings = append(result.Findings, findingFrom(finding))
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package scanrunner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+func TestConstantsMatchInternalValues(t *testing.T) {
+	pairs := map[string]string{
+		StatusPass:       model.StatusPass,
+		StatusFail:       model.StatusFail,
+		StatusSkipped:    model.StatusSkipped,
+		SeverityInfo:     model.SeverityInfo,
+		SeverityLow:      model.SeverityLow,
+		SeverityMedium:   model.SeverityMedium,
+		SeverityHigh:     model.SeverityHigh,
+		SeverityCritical: model.SeverityCritical,
+	}
+	for public, internal := range pairs {
+		if public != internal {
+			t.Errorf("public value %q differs from internal value %q", public, internal)
+		}
+	}
+	if DefaultThreshold != model.DefaultThreshold {
+		t.Errorf("DefaultThreshold = %q, want %q", DefaultThreshold, model.DefaultThreshold)
+	}
+}
+
+// TestFindingFieldsConverted guards against fields added to model.Finding
+// without a public counterpart
+func TestFindingFieldsConverted(t *testing.T) {
+	public := reflect.TypeOf(Finding{})
+	internal := reflect.TypeOf(model.Finding{})
+	if public.NumField() != internal.NumField() {
+		t.Fatalf("Finding has %d fields, model.Finding %d", public.NumField(), internal.NumField())
+	}
+	finding := model.Finding{
+		RuleID: "r", Severity: SeverityHigh, File: "f", Line: 3, Resource: "Deployment/web",
+		Path: "spec", Snippet: "s", Message: "m", Suppressed: true, Justification: "j",
+	}
+	if got := findingFrom(finding).internal(); got != finding {
+		t.Errorf("round trip = %+v, want %+v", got, finding)
+	}
+}
@@ -0,0 +1,114 @@
+package scanrunner_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mtyiska/scanrunner/pkg/scanrunner"
+)
+
+const deployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.27
+`
+
+func ExampleParseRules() {
+	rules, err := scanrunner.ParseRules([]byte(`
+required_fields:
+  - metadata.labels
+  - spec.replicas
+`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(rules.RequiredFields)
+	// Output: [metadata.labels spec.replicas]
+}
+
+func ExampleParseConfig() {
+	config, err := scanrunner.ParseConfig([]byte(`
+scan_path: ./manifests
+fail_on: high
+`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(config.ScanPath, config.FailOn, config.OutputFormat)
+	// Output: ./manifests high json
+}
+
+func ExampleScanner_ValidateManifest() {
+	rules, err := scanrunner.ParseRules([]byte("required_fields: [metadata.labels, spec.selector]"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	scanner := scanrunner.NewScanner(rules)
+
+	result := scanner.ValidateManifest(context.Background(), "deploy.yaml", []byte(deployment))
+	fmt.Println(result.File, result.Status)
+	for _, finding := range result.Findings {
+		fmt.Printf("%s %s line %d: %s\n", finding.Severity, finding.RuleID, finding.Line, finding.Path)
+	}
+	// Output:
+	// deploy.yaml FAIL
+	// medium required-field line 7: spec.selector
+	// low network-policy line 2: kind
+}
+
+func ExampleScanner_ValidateDockerfile() {
+	scanner := scanrunner.NewScanner(scanrunner.DefaultRules())
+	scanner.Threshold = scanrunner.SeverityHigh
+
+	dockerfile := `FROM alpine:latest
+# scanrunner:ignore DL3020 reason=vendored tarball is verified upstream
+ADD vendor.tar.gz /opt/
+RUN apk add curl
+`
+	result := scanner.ValidateDockerfile("Dockerfile", []byte(dockerfile))
+	fmt.Println(result.Status)
+	for _, finding := range result.Findings {
+		fmt.Printf("%s line %d suppressed=%t\n", finding.RuleID, finding.Line, finding.Suppressed)
+	}
+	// Output:
+	// PASS
+	// DL3007 line 1 suppressed=false
+	// DL3020 line 3 suppressed=true
+}
+
+func ExampleScanner_Register() {
+	scanner := scanrunner.NewScanner(scanrunner.DefaultRules())
+	err := scanner.Register(scanrunner.Rule{
+		ID:         "team-label",
+		Severity:   scanrunner.SeverityHigh,
+		Validators: []string{scanrunner.ValidatorKubernetes},
+		Check: func(file scanrunner.File) []scanrunner.Finding {
+			if strings.Contains(string(file.Content), "team:") {
+				return nil
+			}
+			return []scanrunner.Finding{{Message: "every workload needs a team label", Path: "metadata.labels.team"}}
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result := scanner.ValidateManifest(context.Background(), "deploy.yaml", []byte(deployment))
+	for _, finding := range result.Findings {
+		if finding.RuleID == "team-label" {
+			fmt.Printf("%s: %s (%s)\n", finding.File, finding.Message, finding.Severity)
+		}
+	}
+	// Output: deploy.yaml: every workload needs a team label (high)
+}
@@ -0,0 +1,189 @@
+package scanrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/mtyiska/scanrunner/internal/docker"
+	"github.com/mtyiska/scanrunner/internal/engine"
+	"github.com/mtyiska/scanrunner/internal/ignore"
+	"github.com/mtyiska/scanrunner/internal/kubernetes"
+	"github.com/mtyiska/scanrunner/internal/model"
+)
+
+// Names of the built-in validators, as reported in FileResult.Validator
+const (
+	ValidatorKubernetes    = "kubernetes"
+	ValidatorDockerfile    = "dockerfile"
+	ValidatorCompose       = "compose"
+	ValidatorGitHubActions = "github-actions"
+	ValidatorTerraform     = "terraform"
+)
+
+// Rule is a custom rule written in Go. It runs on every file claimed by one
+// of its validators, after the built-in checks.
+type Rule struct {
+	ID         string                    // Reported on findings that do not set their own rule ID
+	Severity   string                    // Reported on findings that do not set their own severity
+	Validators []string                  // Validators whose files the rule checks; every validator when empty
+	Check      func(file File) []Finding // Returns the rule's findings for one file
+}
+
+// File is a file passed to a custom rule
+type File struct {
+	Path      string // Path of the file, or the name given for in-memory content
+	Validator string // Name of the validator that claimed the file
+	Content   []byte // Content of the file
+}
+
+// Scanner validates files with the built-in validators and any registered
+// custom rules. Its fields must not be changed while a validation is running.
+type Scanner struct {
+	Rules              Rules    // Rules the built-in validators check files against
+	Threshold          string   // Lowest severity that fails a file; DefaultThreshold when empty
+	Jobs               int      // Files validated in parallel by Scan; the number of CPUs when 0
+	DockerfilePatterns []string // Extra file name globs Scan treats as Dockerfiles
+
+	ignore IgnoreList // Ignore section of the configuration, merged by Scan with the ignore file
+	custom []Rule     // Custom rules, in registration order
+}
+
+// NewScanner returns a scanner validating files against rules
+func NewScanner(rules Rules) *Scanner {
+	return &Scanner{Rules: rules}
+}
+
+// NewScannerFromConfig returns a scanner set up from a configuration: the
+// rules from its rules path, its fail threshold, Dockerfile patterns and
+// ignore list
+func NewScannerFromConfig(config Config) (*Scanner, error) {
+	rules, err := LoadRules(config.RulesPath)
+	if err != nil {
+		return nil, err
+	}
+	if config.FailOn != "" && !model.ValidSeverity(config.FailOn) {
+		return nil, fmt.Errorf("invalid fail threshold %q (want one of %v)", config.FailOn, model.Severities)
+	}
+	return &Scanner{
+		Rules:              rules,
+		Threshold:          config.FailOn,
+		DockerfilePatterns: config.DockerfilePatterns,
+		ignore:             config.Ignore,
+	}, nil
+}
+
+// Register adds a custom rule to the scanner
+func (s *Scanner) Register(rule Rule) error {
+	switch {
+	case rule.ID == "":
+		return errors.New("custom rule has no ID")
+	case rule.Check == nil:
+		return fmt.Errorf("custom rule %s has no Check function", rule.ID)
+	case !model.ValidSeverity(rule.Severity):
+		return fmt.Errorf("custom rule %s has an invalid severity %q (want one of %v)", rule.ID, rule.Severity, model.Severities)
+	}
+	s.custom = append(s.custom, rule)
+	return nil
+}
+
+// Scan validates every file under root, like the validate command. Paths
+// excluded or suppressed by the configuration's ignore list or a
+// .scanrunnerignore file in root are honoured. Results are not cached. When
+// ctx is cancelled, validation stops and ctx.Err() is returned.
+func (s *Scanner) Scan(ctx context.Context, root string) ([]FileResult, error) {
+	fileList, err := ignore.Load(filepath.Join(root, ignore.FileName))
+	if err != nil {
+		return nil, err
+	}
+	configList := s.ignore.internal()
+	var ignores ignore.List
+	ignores.Merge(&configList)
+	ignores.Merge(fileList)
+	if err := ignores.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid ignore configuration: %w", err)
+	}
+
+	result, err := engine.Run(ctx, engine.Options{
+		Root:               root,
+		Rules:              s.Rules.internal(),
+		DockerfilePatterns: s.DockerfilePatterns,
+		Jobs:               s.Jobs,
+		Ignore:             &ignores,
+		Threshold:          s.Threshold,
+		Checks:             []engine.Check{s.check},
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]FileResult, len(result.Files))
+	for i, file := range result.Files {
+		results[i] = resultFrom(file)
+	}
+	return results, nil
+}
+
+// ValidateManifest validates a Kubernetes manifest held in memory. name is
+// reported as the file of the result and its findings.
+func (s *Scanner) ValidateManifest(ctx context.Context, name string, content []byte) FileResult {
+	findings, err := kubernetes.Validator{}.Validate(ctx, name, content, s.Rules.internal())
+	return s.result(name, ValidatorKubernetes, content, findings, err)
+}
+
+// ValidateDockerfile lints a Dockerfile held in memory. name is reported as
+// the file of the result and its findings. The build context and .dockerignore
+// checks and the Trivy secret scan need files on disk and are skipped; use
+// Scan to run them.
+func (s *Scanner) ValidateDockerfile(name string, content []byte) FileResult {
+	findings, err := docker.LintDockerfile(name, content)
+	return s.result(name, ValidatorDockerfile, content, findings, err)
+}
+
+// result adds the findings of custom rules to the findings of a validator and
+// derives the file's status
+func (s *Scanner) result(name, validator string, content []byte, findings []model.Finding, err error) FileResult {
+	result := model.FileResult{File: name, Validator: validator}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = fmt.Sprintf("%s validation failed: %v", validator, err)
+		return resultFrom(result)
+	}
+	result.Findings = append(findings, s.check(result, content)...)
+
+	results := []model.FileResult{result}
+	model.ApplyThreshold(results, s.threshold())
+	return resultFrom(results[0])
+}
+
+// check runs the custom rules that apply to a validated file
+func (s *Scanner) check(result model.FileResult, content []byte) []model.Finding {
+	var findings []model.Finding
+	for _, rule := range s.custom {
+		if len(rule.Validators) > 0 && !slices.Contains(rule.Validators, result.Validator) {
+			continue
+		}
+		for _, finding := range rule.Check(File{Path: result.File, Validator: result.Validator, Content: content}) {
+			if finding.RuleID == "" {
+				finding.RuleID = rule.ID
+			}
+			if finding.Severity == "" {
+				finding.Severity = rule.Severity
+			}
+			if finding.File == "" {
+				finding.File = result.File
+			}
+			findings = append(findings, finding.internal())
+		}
+	}
+	return findings
+}
+
+// threshold returns the fail threshold, defaulting to DefaultThreshold
+func (s *Scanner) threshold() string {
+	if s.Threshold == "" {
+		return DefaultThreshold
+	}
+	return s.Threshold
+}
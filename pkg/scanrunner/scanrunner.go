@@ -0,0 +1,132 @@
+// Package scanrunner is the public Go API for embedding scanrunner in other
+// programs. It loads configuration and rules, validates files on disk or held
+// in memory, runs custom rules written in Go and returns typed findings.
+//
+// The package follows semantic versioning as given by Version: within a major
+// version, exported identifiers are only added, never removed or changed.
+package scanrunner
+
+import (
+	"github.com/mtyiska/scanrunner/pkg"
+)
+
+// Version is the semantic version of this package and of the scanrunner CLI
+const Version = "v1.1.0"
+
+// Config is the scanrunner configuration, as read from a config.yaml file
+type Config struct {
+	OutputFormat string // Report format, e.g. "json" or "markdown"
+	ScanPath     string // Directory to scan
+	RulesPath    string // Path to the rules file
+	ReportOutput string // Path the report is written to
+	StrictMode   bool   // Stop validation at the first failing file
+	FailOn       string // Lowest severity that fails a file
+
+	DockerfilePatterns []string // Extra file name globs treated as Dockerfiles
+	CacheDir           string   // Directory for cached validation results
+
+	Ignore IgnoreList // Path excludes and expiring suppressions, merged with .scanrunnerignore
+}
+
+// IgnoreList holds path excludes and time-limited finding suppressions
+type IgnoreList struct {
+	Exclude  []string      // Path globs never scanned, e.g. "vendor/**"
+	Suppress []Suppression // Findings to suppress until an expiry date
+}
+
+// Suppression suppresses the findings matching all of its selectors. An empty
+// selector matches everything, but at least one selector must be set.
+type Suppression struct {
+	Paths     []string // File globs relative to the scan root
+	Rules     []string // Rule IDs
+	Resources []string // Resource globs, e.g. "Deployment/legacy-*"
+	Owner     string   // Who is accountable for the exception
+	Reason    string   // Why the exception is acceptable
+	Expires   string   // Last day the suppression applies, as YYYY-MM-DD
+}
+
+// Rules is the set of compliance rules files are validated against
+type Rules struct {
+	RequiredFields []string // Field paths every Kubernetes workload must set
+}
+
+// Finding is a single rule violation
+type Finding struct {
+	RuleID   string `json:"rule_id"`            // Stable identifier of the rule that produced the finding
+	Severity string `json:"severity"`           // One of the Severity values
+	File     string `json:"file"`               // File the finding applies to
+	Line     int    `json:"line,omitempty"`     // 1-based line number, 0 when unknown
+	Resource string `json:"resource,omitempty"` // Resource within the file, e.g. "Deployment/web"
+	Path     string `json:"path,omitempty"`     // Field path within the file, when the finding concerns a field
+	Snippet  string `json:"snippet,omitempty"`  // Offending text, e.g. a Dockerfile instruction
+	Message  string `json:"message"`            // Human-readable description
+
+	Suppressed    bool   `json:"suppressed,omitempty"`    // Silenced by an annotation, comment or ignore entry; kept for audit
+	Justification string `json:"justification,omitempty"` // Reason given for the suppression
+}
+
+// FileResult is the outcome of validating one file
+type FileResult struct {
+	File      string    `json:"file"`                // Path of the validated file
+	Validator string    `json:"validator,omitempty"` // Name of the validator that claimed the file
+	Status    string    `json:"status"`              // StatusPass, StatusFail or StatusSkipped
+	Findings  []Finding `json:"findings,omitempty"`  // Findings reported for the file
+	Error     string    `json:"error,omitempty"`     // Reason the file could not be validated or was skipped
+}
+
+// Status values of a FileResult
+const (
+	StatusPass    = "PASS"
+	StatusFail    = "FAIL"
+	StatusSkipped = "SKIPPED"
+)
+
+// Severity values of a Finding, from least to most severe
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// DefaultThreshold is the lowest severity that fails a file unless another
+// threshold is set
+const DefaultThreshold = SeverityMedium
+
+// DefaultConfig returns the configuration used when no file is given
+func DefaultConfig() Config {
+	return configFrom(pkg.DefaultConfig())
+}
+
+// LoadConfig reads a configuration file and applies the SCANRUNNER_*
+// environment variable overrides. A missing file yields the defaults.
+func LoadConfig(path string) (Config, error) {
+	config, err := pkg.LoadConfig(path)
+	return configFrom(config), err
+}
+
+// ParseConfig parses the content of a configuration file. Environment
+// variables are not consulted.
+func ParseConfig(data []byte) (Config, error) {
+	config, err := pkg.ParseConfig(data)
+	return configFrom(config), err
+}
+
+// DefaultRules returns the rules used when no rules file is given
+func DefaultRules() Rules {
+	return rulesFrom(pkg.DefaultRules())
+}
+
+// LoadRules reads and validates a rules file. A missing file yields the
+// default rules.
+func LoadRules(path string) (Rules, error) {
+	rules, err := pkg.LoadRules(path)
+	return rulesFrom(rules), err
+}
+
+// ParseRules parses and validates the content of a rules file
+func ParseRules(data []byte) (Rules, error) {
+	rules, err := pkg.ParseRules(data)
+	return rulesFrom(rules), err
+}